@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
@@ -99,3 +100,16 @@ func CreateAggregate(aggregateType AggregateType, id string) (Aggregate, error)
 	}
 	return nil, ErrAggregateNotRegistered
 }
+
+// RegisteredAggregateTypes returns the types registered with RegisterAggregate,
+// sorted by name.
+func RegisteredAggregateTypes() []AggregateType {
+	aggregatesMu.RLock()
+	defer aggregatesMu.RUnlock()
+	types := make([]AggregateType, 0, len(aggregates))
+	for aggregateType := range aggregates {
+		types = append(types, aggregateType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
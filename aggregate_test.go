@@ -45,6 +45,35 @@ func TestCreateAggregate(t *testing.T) {
 	}
 }
 
+func TestRegisteredAggregateTypes(t *testing.T) {
+	if containsAggregateType(RegisteredAggregateTypes(), TestAggregateRegisterListedType) {
+		t.Error("the aggregate type should not be listed before it is registered")
+	}
+
+	RegisterAggregate(func(id string) Aggregate {
+		return &TestAggregateRegisterListed{id: id}
+	})
+
+	types := RegisteredAggregateTypes()
+	if !containsAggregateType(types, TestAggregateRegisterListedType) {
+		t.Error("the aggregate type should be listed:", types)
+	}
+	for i := 1; i < len(types); i++ {
+		if types[i-1] >= types[i] {
+			t.Error("the aggregate types should be sorted:", types)
+		}
+	}
+}
+
+func containsAggregateType(types []AggregateType, aggregateType AggregateType) bool {
+	for _, t := range types {
+		if t == aggregateType {
+			return true
+		}
+	}
+	return false
+}
+
 func TestRegisterAggregateEmptyName(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil || r != "eventhorizon: attempt to register empty aggregate type" {
@@ -80,9 +109,10 @@ func TestRegisterAggregateTwice(t *testing.T) {
 }
 
 const (
-	TestAggregateRegisterType      AggregateType = "TestAggregateRegister"
-	TestAggregateRegisterEmptyType AggregateType = ""
-	TestAggregateRegisterTwiceType AggregateType = "TestAggregateRegisterTwice"
+	TestAggregateRegisterType       AggregateType = "TestAggregateRegister"
+	TestAggregateRegisterEmptyType  AggregateType = ""
+	TestAggregateRegisterTwiceType  AggregateType = "TestAggregateRegisterTwice"
+	TestAggregateRegisterListedType AggregateType = "TestAggregateRegisterListed"
 )
 
 type TestAggregateRegister struct {
@@ -129,3 +159,18 @@ func (a *TestAggregateRegisterTwice) AggregateType() AggregateType {
 func (a *TestAggregateRegisterTwice) HandleCommand(ctx context.Context, cmd Command) error {
 	return nil
 }
+
+type TestAggregateRegisterListed struct {
+	id string
+}
+
+var _ = Aggregate(&TestAggregateRegisterListed{})
+
+func (a *TestAggregateRegisterListed) EntityID() string { return a.id }
+
+func (a *TestAggregateRegisterListed) AggregateType() AggregateType {
+	return TestAggregateRegisterListedType
+}
+func (a *TestAggregateRegisterListed) HandleCommand(ctx context.Context, cmd Command) error {
+	return nil
+}
@@ -17,6 +17,11 @@ package events
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/kr/pretty"
+
 	eh "github.com/firawe/eventhorizon"
 )
 
@@ -33,7 +38,11 @@ var ErrInvalidAggregateType = errors.New("invalid aggregate type")
 
 var ErrInvalidSnapshot = errors.New("invalid snapshot")
 
-var ErrNotFound = errors.New("snapshot not found")
+// ErrNotFound is when there is no snapshot, it is eh.ErrSnapshotNotFound.
+var ErrNotFound = eh.ErrSnapshotNotFound
+
+// ErrInvalidVersion is when an aggregate is reconstructed at a version it does not have.
+var ErrInvalidVersion = errors.New("invalid version")
 
 // ErrMismatchedEventType occurs when loaded events from ID does not match aggregate type.
 var ErrMismatchedEventType = errors.New("mismatched event type and aggregate type")
@@ -140,7 +149,7 @@ func (r *AggregateStore) Load(ctx context.Context, aggregateType eh.AggregateTyp
 		return nil, ErrInvalidAggregateType
 	}
 	var aggregate eh.Aggregate
-	if r.snapshotStore != nil {
+	if r.snapshotStore != nil && !eh.WithoutSnapshotFromContext(ctx) {
 		aggregate, err = r.snapshotStore.Load(ctx, a.AggregateType(), id, -1)
 		if err != nil {
 			if err != ErrNotFound {
@@ -152,38 +161,117 @@ func (r *AggregateStore) Load(ctx context.Context, aggregateType eh.AggregateTyp
 		}
 	}
 
-	var events []eh.Event
+	if err := r.applyEvents(ctx, a, id); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// CurrentState hydrates the aggregate with its current state, by copying the
+// latest snapshot into it, if there is one, and applying the events after it.
+// The aggregate must be a pointer of the type registered for the aggregate type,
+// as the snapshot store creates the snapshot aggregates as registered.
+func (r *AggregateStore) CurrentState(ctx context.Context, id string, agg eh.Aggregate) error {
+	a, ok := agg.(Aggregate)
+	if !ok {
+		return ErrInvalidAggregateType
+	}
+
+	if r.snapshotStore != nil && !eh.WithoutSnapshotFromContext(ctx) {
+		snapshot, err := r.snapshotStore.Load(ctx, a.AggregateType(), id, -1)
+		if err != nil && err != ErrNotFound {
+			return err
+		} else if err == nil {
+			dst, src := reflect.ValueOf(a), reflect.ValueOf(snapshot)
+			if dst.Kind() != reflect.Ptr || dst.Type() != src.Type() {
+				return ErrInvalidSnapshot
+			}
+			dst.Elem().Set(src.Elem())
+		}
+	}
+
+	return r.applyEvents(ctx, a, id)
+}
+
+// DiffVersions reconstructs an aggregate at two versions and returns the
+// differences between the states, one per line, or an empty string if there
+// are none. The aggregate is only used for its type, the states are created
+// with the factory registered for the type. Version 0 is the new aggregate.
+func (r *AggregateStore) DiffVersions(ctx context.Context, id string, v1, v2 int, agg eh.Aggregate) (string, error) {
+	a1, err := r.loadVersion(ctx, agg.AggregateType(), id, v1)
+	if err != nil {
+		return "", err
+	}
+	a2, err := r.loadVersion(ctx, agg.AggregateType(), id, v2)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(pretty.Diff(a1, a2), "\n"), nil
+}
+
+// loadVersion creates an aggregate and applies the events up to the version.
+func (r *AggregateStore) loadVersion(ctx context.Context, aggregateType eh.AggregateType, id string, version int) (Aggregate, error) {
+	agg, err := eh.CreateAggregate(aggregateType, id)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := agg.(Aggregate)
+	if !ok {
+		return nil, ErrInvalidAggregateType
+	}
+	if version < 0 {
+		return nil, ErrInvalidVersion
+	} else if version == 0 {
+		return a, nil
+	}
+
+	events, ctx, err := r.store.Load(eh.NewContextWithLoadOptions(ctx, eh.LoadOptions{
+		MaxVersion: version,
+	}), id)
+	if esErr, ok := err.(eh.EventStoreError); ok && esErr.Err == eh.ErrAggregateNotFound {
+		return nil, ErrInvalidVersion
+	} else if err != nil {
+		return nil, err
+	}
+	if err := FoldAggregate(ctx, a, events); err != nil {
+		return nil, err
+	}
+	if a.Version() != version {
+		return nil, ErrInvalidVersion
+	}
+
+	return a, nil
+}
+
+// applyEvents applies the events after the current version of the aggregate,
+// loaded from the event store in batches.
+func (r *AggregateStore) applyEvents(ctx context.Context, a Aggregate, id string) error {
 	batchSize := 5
-	minVersion := 1
 	value, ok := ctx.Value("batchsize").(int)
 	if ok {
 		batchSize = value
 	}
-	if a.Version() != 0 {
-		minVersion = a.Version() + 1
-	}
 
-	ctx = context.WithValue(ctx, "minVersion", minVersion)
-	ctx = context.WithValue(ctx, "limit", batchSize)
-	events, ctx, err = r.store.Load(ctx, id)
-	for i := 1; ; i++ {
-		if err = r.applyEvents(ctx, a, events); err != nil {
-			return nil, err
+	for {
+		events, ctx, err := r.store.Load(eh.NewContextWithLoadOptions(ctx, eh.LoadOptions{
+			MinVersion: a.Version() + 1,
+			Limit:      batchSize,
+		}), id)
+		if esErr, ok := err.(eh.EventStoreError); ok && esErr.Err == eh.ErrAggregateNotFound {
+			// A new aggregate, without any events.
+			return nil
+		} else if err != nil {
+			return err
 		}
-		ctx = context.WithValue(ctx, "minVersion", batchSize*i+a.Version()+1)
-		if len(events) < batchSize {
-			break
+		if err := FoldAggregate(ctx, a, events); err != nil {
+			return err
 		}
-		events, ctx, err = r.store.Load(ctx, id)
-		if err != nil {
-			return a, err
-		}
-		if len(events) == 0 {
-			break
+		if len(events) < batchSize {
+			return nil
 		}
 	}
-
-	return a, nil
 }
 
 // Save implements the Save method of the eventhorizon.AggregateStore interface.
@@ -212,21 +300,25 @@ func (r *AggregateStore) Save(ctx context.Context, agg eh.Aggregate) error {
 		}
 	}
 
-	if err := r.store.Save(ctx, events, a.Version()); err != nil {
+	// The events are saved even if they could not be published.
+	err := r.store.Save(ctx, events, a.Version())
+	if _, ok := err.(eh.PublishError); err != nil && !ok {
 		return err
 	}
 	a.ClearEvents()
 
 	// Apply the events in case the aggregate needs to be further used
 	// after this save. Currently it is not reused.
-	//if err := r.applyEvents(ctx, a, events); err != nil {
+	//if err := FoldAggregate(ctx, a, events); err != nil {
 	//	return err
 	//}
 
-	return nil
+	return err
 }
 
-func (r *AggregateStore) applyEvents(ctx context.Context, a Aggregate, events []eh.Event) error {
+// FoldAggregate folds a sequence of events into an aggregate by applying them
+// in order and incrementing the version for each applied event.
+func FoldAggregate(ctx context.Context, a Aggregate, events []eh.Event) error {
 	for _, event := range events {
 		if event.AggregateType() != a.AggregateType() {
 			return ErrMismatchedEventType
@@ -17,11 +17,14 @@ package events
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/eventstore/memory"
 	"github.com/firawe/eventhorizon/mocks"
 	"github.com/google/uuid"
 )
@@ -218,6 +221,27 @@ func TestAggregateStore_SaveEvents(t *testing.T) {
 	}
 }
 
+func TestAggregateStore_SavePublishError(t *testing.T) {
+	store, eventStore, _ := createStore(t)
+
+	ctx := context.Background()
+	id := uuid.New().String()
+	agg := NewTestAggregateOther(id)
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event := agg.StoreEvent(mocks.EventType, &mocks.EventData{Content: "event"}, timestamp)
+
+	// The events are saved by the store, only publishing failed.
+	publishErr := eh.PublishError{Err: errors.New("bus error"), Event: event}
+	eventStore.Err = publishErr
+	err := store.Save(ctx, agg)
+	if err != publishErr {
+		t.Error("there should be a publish error:", err)
+	}
+	if len(agg.Events()) != 0 {
+		t.Error("there should be no uncommitted events:", agg.Events())
+	}
+}
+
 func TestAggregateStore_AggregateNotRegistered(t *testing.T) {
 	store, _, _ := createStore(t)
 
@@ -233,6 +257,194 @@ func TestAggregateStore_AggregateNotRegistered(t *testing.T) {
 	}
 }
 
+func TestFoldAggregate(t *testing.T) {
+	ctx := context.Background()
+
+	id := uuid.New().String()
+	agg := NewTestAggregate(id)
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event1 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, TestAggregateType, id, 1)
+	event2 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event2"},
+		timestamp, TestAggregateType, id, 2)
+	if err := FoldAggregate(ctx, agg, []eh.Event{event1, event2}); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if agg.Version() != 2 {
+		t.Error("the version should be 2:", agg.Version())
+	}
+	if !reflect.DeepEqual(agg.event, event2) {
+		t.Error("the last event should be applied:", agg.event)
+	}
+
+	otherEvent := eh.NewEventForAggregate(mocks.EventType, nil,
+		timestamp, TestAggregateOtherType, id, 3)
+	if err := FoldAggregate(ctx, agg, []eh.Event{otherEvent}); err != ErrMismatchedEventType {
+		t.Error("there should be a ErrMismatchedEventType error:", err)
+	}
+}
+
+func TestAggregateStore_CurrentState(t *testing.T) {
+	ctx := context.Background()
+
+	eventStore := memory.NewEventStore()
+	id := uuid.New().String()
+	agg := NewTestAggregate(id)
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	var events []eh.Event
+	for i := 0; i < 7; i++ {
+		events = append(events, agg.StoreEvent(TestAggregateEventType,
+			&TestEventData{Content: fmt.Sprintf("event%d", i+1)}, timestamp))
+	}
+	if err := eventStore.Save(ctx, events, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	t.Log("hydrate from the events only")
+	store, err := NewAggregateStore(eventStore, &mocks.EventBus{})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	full := NewTestAggregate(id)
+	if err := store.CurrentState(ctx, id, full); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if full.Version() != 7 {
+		t.Error("the version should be 7:", full.Version())
+	}
+
+	t.Log("hydrate from a snapshot and the events after it")
+	snapshot := NewTestAggregate(id)
+	if err := FoldAggregate(ctx, snapshot, events[:4]); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	snapshotStore := &testSnapshotStore{}
+	if err := snapshotStore.Save(ctx, snapshot); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	store, err = NewAggregateStoreOptions(Options{
+		Store:         eventStore,
+		SnapshotStore: snapshotStore,
+	})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	merged := NewTestAggregate(id)
+	if err := store.CurrentState(ctx, id, merged); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if !reflect.DeepEqual(merged, full) {
+		t.Error("the aggregate should be correct:", merged, full)
+	}
+
+	t.Log("hydrate an aggregate of another type")
+	other := NewTestAggregateOther(id)
+	if err := store.CurrentState(ctx, id, other); err != ErrInvalidSnapshot {
+		t.Error("there should be a invalid snapshot error:", err)
+	}
+}
+
+func TestAggregateStore_LoadWithoutSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	eventStore := memory.NewEventStore()
+	id := uuid.New().String()
+	agg := NewTestAggregate(id)
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	var events []eh.Event
+	for i := 0; i < 7; i++ {
+		events = append(events, agg.StoreEvent(TestAggregateEventType,
+			&TestEventData{Content: fmt.Sprintf("event%d", i+1)}, timestamp))
+	}
+	if err := eventStore.Save(ctx, events, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	snapshot := NewTestAggregate(id)
+	if err := FoldAggregate(ctx, snapshot, events[:4]); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	snapshotStore := &testSnapshotStore{}
+	if err := snapshotStore.Save(ctx, snapshot); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	store, err := NewAggregateStoreOptions(Options{
+		Store:         eventStore,
+		SnapshotStore: snapshotStore,
+	})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	fromSnapshot, err := store.Load(ctx, TestAggregateType, id)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if snapshotStore.loads != 1 {
+		t.Error("the snapshot should be loaded:", snapshotStore.loads)
+	}
+
+	t.Log("load from all events")
+	full, err := store.Load(eh.NewContextWithoutSnapshot(ctx), TestAggregateType, id)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if snapshotStore.loads != 1 {
+		t.Error("the snapshot should not be loaded:", snapshotStore.loads)
+	}
+	if full.(Aggregate).Version() != 7 {
+		t.Error("the version should be 7:", full.(Aggregate).Version())
+	}
+	if !reflect.DeepEqual(fromSnapshot, full) {
+		t.Error("the aggregate loaded from the snapshot should be correct:", fromSnapshot, full)
+	}
+}
+
+func TestAggregateStore_DiffVersions(t *testing.T) {
+	ctx := context.Background()
+
+	eventStore := memory.NewEventStore()
+	store, err := NewAggregateStore(eventStore, &mocks.EventBus{})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	id := uuid.New().String()
+	agg := NewTestAggregate(id)
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	var events []eh.Event
+	for i := 0; i < 4; i++ {
+		events = append(events, agg.StoreEvent(TestAggregateEventType,
+			&TestEventData{Content: fmt.Sprintf("event%d", i+1)}, timestamp))
+	}
+	if err := eventStore.Save(ctx, events, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	diff, err := store.DiffVersions(ctx, id, 2, 4, agg)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	for _, change := range []string{"2 != 4", `"event2" != "event4"`} {
+		if !strings.Contains(diff, change) {
+			t.Error("the diff should contain the change:", change)
+		}
+	}
+
+	t.Log("same version")
+	if diff, err := store.DiffVersions(ctx, id, 3, 3, agg); err != nil || diff != "" {
+		t.Error("there should be no diff:", diff, err)
+	}
+
+	t.Log("version after the last event")
+	if _, err := store.DiffVersions(ctx, id, 2, 5, agg); err != ErrInvalidVersion {
+		t.Error("there should be a ErrInvalidVersion error:", err)
+	}
+	if _, err := store.DiffVersions(ctx, uuid.New().String(), 0, 1, agg); err != ErrInvalidVersion {
+		t.Error("there should be a ErrInvalidVersion error:", err)
+	}
+}
+
 func createStore(t *testing.T) (*AggregateStore, *mocks.EventStore, *mocks.EventBus) {
 	eventStore := &mocks.EventStore{
 		Events: make([]eh.Event, 0),
@@ -290,3 +502,26 @@ func (a *TestAggregateOther) ApplySnapshot(context.Context, eh.Snapshot) error {
 	return nil
 }
 
+type testSnapshotStore struct {
+	snapshot *TestAggregate
+	loads    int
+}
+
+func (s *testSnapshotStore) Save(ctx context.Context, a eh.Aggregate) error {
+	agg := *a.(*TestAggregate)
+	base := *agg.AggregateBase
+	agg.AggregateBase = &base
+	s.snapshot = &agg
+	return nil
+}
+
+func (s *testSnapshotStore) Load(ctx context.Context, aggregateType eh.AggregateType, id string, version int) (eh.Aggregate, error) {
+	s.loads++
+	if s.snapshot == nil {
+		return nil, ErrNotFound
+	}
+	agg := *s.snapshot
+	base := *agg.AggregateBase
+	agg.AggregateBase = &base
+	return &agg, nil
+}
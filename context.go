@@ -69,11 +69,15 @@ func init() {
 
 type contextKey int
 
-// Context keys for namespace and min version.
+// Context keys for namespace, min version and loading.
 const (
 	namespaceKey contextKey = iota
 	aggregateTypeKey
 	minVersionKey
+	loadLimitKey
+	loadMinVersionKey
+	loadOptionsKey
+	withoutSnapshotKey
 )
 
 // Strings used to marshal context values.
@@ -110,6 +114,12 @@ func NewContextWithNamespaceAndType(ctx context.Context, namespace, aggregateTyp
 	return context.WithValue(ctx, aggregateTypeKey, aggregateType)
 }
 
+// NewScopedContext sets both the namespace and the aggregate type to use in
+// the context, which select the database and collections of the stores.
+func NewScopedContext(ctx context.Context, namespace string, aggregateType AggregateType) context.Context {
+	return NewContextWithNamespaceAndType(ctx, namespace, string(aggregateType))
+}
+
 // MinVersionFromContext returns the min version from the context.
 func MinVersionFromContext(ctx context.Context) (int, bool) {
 	minVersion, ok := ctx.Value(minVersionKey).(int)
@@ -128,6 +138,105 @@ func NewContextWithMinVersionWait(ctx context.Context, minVersion int) (c contex
 	return context.WithTimeout(ctx, DefaultMinVersionDeadline)
 }
 
+// WithoutSnapshotFromContext returns if loading should ignore any snapshots and
+// apply all events.
+func WithoutSnapshotFromContext(ctx context.Context) bool {
+	without, _ := ctx.Value(withoutSnapshotKey).(bool)
+	return without
+}
+
+// NewContextWithoutSnapshot returns the context set to load aggregates from all
+// their events, ignoring any snapshots. Useful to verify snapshots against a
+// full replay.
+func NewContextWithoutSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutSnapshotKey, true)
+}
+
+// LoadLimitFromContext returns the max number of events to load by the event
+// store from the context.
+func LoadLimitFromContext(ctx context.Context) (int, bool) {
+	if limit, ok := ctx.Value(loadLimitKey).(int); ok {
+		return limit, true
+	}
+	// Deprecated: the untyped key is only supported for one more release,
+	// use NewContextWithLoadLimit instead.
+	limit, ok := ctx.Value("limit").(int)
+	return limit, ok
+}
+
+// NewContextWithLoadLimit returns the context with the max number of events to
+// load by the event store set, used to load large aggregates in batches.
+func NewContextWithLoadLimit(ctx context.Context, limit int) context.Context {
+	return context.WithValue(ctx, loadLimitKey, limit)
+}
+
+// LoadMinVersionFromContext returns the version of the first event to load by
+// the event store from the context.
+func LoadMinVersionFromContext(ctx context.Context) (int, bool) {
+	if minVersion, ok := ctx.Value(loadMinVersionKey).(int); ok {
+		return minVersion, true
+	}
+	// Deprecated: the untyped key is only supported for one more release,
+	// use NewContextWithLoadMinVersion instead.
+	minVersion, ok := ctx.Value("minVersion").(int)
+	return minVersion, ok
+}
+
+// NewContextWithLoadMinVersion returns the context with the version of the
+// first event to load by the event store set. It is used together with
+// NewContextWithLoadLimit to load the next batch of events.
+func NewContextWithLoadMinVersion(ctx context.Context, minVersion int) context.Context {
+	return context.WithValue(ctx, loadMinVersionKey, minVersion)
+}
+
+// LoadSort is the order of the events loaded by the event store.
+type LoadSort int
+
+const (
+	// LoadSortAscending loads the events from the lowest version.
+	LoadSortAscending LoadSort = iota
+	// LoadSortDescending loads the events from the highest version.
+	LoadSortDescending
+)
+
+// LoadOptions are the options for loading events by the event store. The zero
+// value loads all events in ascending version order.
+type LoadOptions struct {
+	// MinVersion is the version of the first event to load, 0 for no min.
+	MinVersion int
+	// MaxVersion is the version of the last event to load, 0 for no max.
+	MaxVersion int
+	// Limit is the max number of events to load, 0 for no limit.
+	Limit int
+	// Sort is the version order of the loaded events.
+	Sort LoadSort
+}
+
+// LoadOptionsFromContext returns the load options from the context. If not set
+// it falls back to the limit and min version set with NewContextWithLoadLimit
+// and NewContextWithLoadMinVersion, or the deprecated untyped keys.
+func LoadOptionsFromContext(ctx context.Context) (LoadOptions, bool) {
+	if opts, ok := ctx.Value(loadOptionsKey).(LoadOptions); ok {
+		return opts, true
+	}
+	opts := LoadOptions{}
+	limit, hasLimit := LoadLimitFromContext(ctx)
+	minVersion, hasMinVersion := LoadMinVersionFromContext(ctx)
+	if hasLimit {
+		opts.Limit = limit
+	}
+	if hasMinVersion {
+		opts.MinVersion = minVersion
+	}
+	return opts, hasLimit || hasMinVersion
+}
+
+// NewContextWithLoadOptions returns the context with the options for loading
+// events by the event store set.
+func NewContextWithLoadOptions(ctx context.Context, opts LoadOptions) context.Context {
+	return context.WithValue(ctx, loadOptionsKey, opts)
+}
+
 // Private context marshaling funcs.
 var (
 	contextMarshalFuncs   = []ContextMarshalFunc{}
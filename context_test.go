@@ -53,6 +53,22 @@ func TestContextNamespace(t *testing.T) {
 	}
 }
 
+func TestScopedContext(t *testing.T) {
+	ctx := NewScopedContext(context.Background(), "ns", "agg")
+	if ns := NamespaceFromContext(ctx); ns != "ns" {
+		t.Error("the namespace should be correct:", ns)
+	}
+	if aggregateType := AggregateTypeFromContext(ctx); aggregateType != "agg" {
+		t.Error("the aggregate type should be correct:", aggregateType)
+	}
+
+	vals := MarshalContext(ctx)
+	ctx = UnmarshalContext(vals)
+	if ns, aggregateType := NamespaceFromContext(ctx), AggregateTypeFromContext(ctx); ns != "ns" || aggregateType != "agg" {
+		t.Error("the scope should be kept when marshaled:", ns, aggregateType)
+	}
+}
+
 func TestContextMinVersion(t *testing.T) {
 	ctx := context.Background()
 
@@ -86,6 +102,78 @@ func TestContextMinVersion(t *testing.T) {
 	}
 }
 
+func TestContextLoadLimit(t *testing.T) {
+	ctx := context.Background()
+
+	if v, ok := LoadLimitFromContext(ctx); ok {
+		t.Error("there should be no load limit:", v)
+	}
+	if v, ok := LoadMinVersionFromContext(ctx); ok {
+		t.Error("there should be no load min version:", v)
+	}
+
+	ctx = NewContextWithLoadLimit(ctx, 5)
+	ctx = NewContextWithLoadMinVersion(ctx, 11)
+	if v, ok := LoadLimitFromContext(ctx); !ok || v != 5 {
+		t.Error("the load limit should be correct:", v)
+	}
+	if v, ok := LoadMinVersionFromContext(ctx); !ok || v != 11 {
+		t.Error("the load min version should be correct:", v)
+	}
+
+	t.Log("deprecated untyped keys")
+	ctx = context.WithValue(context.Background(), "limit", 3)
+	ctx = context.WithValue(ctx, "minVersion", 7)
+	if v, ok := LoadLimitFromContext(ctx); !ok || v != 3 {
+		t.Error("the load limit should be correct:", v)
+	}
+	if v, ok := LoadMinVersionFromContext(ctx); !ok || v != 7 {
+		t.Error("the load min version should be correct:", v)
+	}
+	ctx = NewContextWithLoadLimit(ctx, 5)
+	if v, ok := LoadLimitFromContext(ctx); !ok || v != 5 {
+		t.Error("the typed load limit should have precedence:", v)
+	}
+}
+
+func TestContextLoadOptions(t *testing.T) {
+	ctx := context.Background()
+
+	if opts, ok := LoadOptionsFromContext(ctx); ok || opts != (LoadOptions{}) {
+		t.Error("there should be no load options:", opts)
+	}
+
+	expected := LoadOptions{
+		MinVersion: 3,
+		MaxVersion: 8,
+		Limit:      4,
+		Sort:       LoadSortDescending,
+	}
+	ctx = NewContextWithLoadOptions(ctx, expected)
+	if opts, ok := LoadOptionsFromContext(ctx); !ok || opts != expected {
+		t.Error("the load options should be correct:", opts)
+	}
+
+	t.Log("fall back to the limit and min version")
+	ctx = NewContextWithLoadLimit(context.Background(), 5)
+	ctx = context.WithValue(ctx, "minVersion", 7)
+	opts, ok := LoadOptionsFromContext(ctx)
+	if !ok || opts != (LoadOptions{MinVersion: 7, Limit: 5}) {
+		t.Error("the load options should be correct:", opts)
+	}
+}
+
+func TestContextWithoutSnapshot(t *testing.T) {
+	ctx := context.Background()
+	if WithoutSnapshotFromContext(ctx) {
+		t.Error("snapshots should be used by default")
+	}
+	ctx = NewContextWithoutSnapshot(ctx)
+	if !WithoutSnapshotFromContext(ctx) {
+		t.Error("snapshots should be ignored")
+	}
+}
+
 func TestContextMarshaler(t *testing.T) {
 	if len(contextMarshalFuncs) != 2 {
 		t.Error("there should be two context marshalers")
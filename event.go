@@ -18,6 +18,8 @@ package eventhorizon
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 )
@@ -28,6 +30,14 @@ type EventType string
 // EventData is any additional data for an event.
 type EventData interface{}
 
+// DefaultsProvider is event data that sets defaults for its fields. Event
+// stores call ApplyDefaults on new event data before decoding a stored event
+// into it, so that fields missing in events stored before the fields were
+// added keep their defaults instead of the zero values.
+type DefaultsProvider interface {
+	ApplyDefaults()
+}
+
 // Event is a domain event describing a change that has happened to an aggregate.
 //
 // An event struct and type name should:
@@ -57,6 +67,15 @@ type Event interface {
 	String() string
 }
 
+// DataErrorer is an event that decodes its data lazily, on the first call to
+// Data, which returns nil if the data could not be decoded. DataErr returns
+// the error of decoding the data, if any.
+type DataErrorer interface {
+	Event
+
+	DataErr() error
+}
+
 // NewEvent creates a new event with a type and data, setting its timestamp.
 func NewEvent(eventType EventType, data EventData, timestamp time.Time) Event {
 	return event{
@@ -157,9 +176,6 @@ var ErrEventDataNotRegistered = errors.New("event data not registered")
 // An example would be:
 //     RegisterEventData(MyEventType, func() Event { return &MyEventData{} })
 func RegisterEventData(eventType EventType, factory func() EventData) {
-	// TODO: Explore the use of reflect/gob for creating concrete types without
-	// a factory func.
-
 	if eventType == EventType("") {
 		panic("eventhorizon: attempt to register empty event type")
 	}
@@ -172,6 +188,26 @@ func RegisterEventData(eventType EventType, factory func() EventData) {
 	eventDataFactories[eventType] = factory
 }
 
+// RegisterEventType registers the type of a sample event data for a type. It
+// works as RegisterEventData, but uses reflection to create a pointer to a new
+// value of the same type as the sample instead of a factory func.
+//
+// An example would be:
+//     RegisterEventType(MyEventType, &MyEventData{})
+func RegisterEventType(eventType EventType, sample EventData) {
+	if sample == nil {
+		panic(fmt.Sprintf("eventhorizon: attempt to register nil sample for %q", eventType))
+	}
+
+	t := reflect.TypeOf(sample)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	RegisterEventData(eventType, func() EventData {
+		return reflect.New(t).Interface()
+	})
+}
+
 // UnregisterEventData removes the registration of the event data factory for
 // a type. This is mainly useful in mainenance situations where the event data
 // needs to be switched in a migrations.
@@ -188,6 +224,19 @@ func UnregisterEventData(eventType EventType) {
 	delete(eventDataFactories, eventType)
 }
 
+// RegisteredEventTypes returns the types registered with RegisterEventData,
+// sorted by name.
+func RegisteredEventTypes() []EventType {
+	eventDataFactoriesMu.RLock()
+	defer eventDataFactoriesMu.RUnlock()
+	types := make([]EventType, 0, len(eventDataFactories))
+	for eventType := range eventDataFactories {
+		types = append(types, eventType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
 // CreateEventData creates an event data of a type using the factory registered
 // with RegisterEventData.
 func CreateEventData(eventType EventType) (EventData, error) {
@@ -15,6 +15,7 @@
 package eventhorizon
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -88,6 +89,84 @@ func TestCreateEventData(t *testing.T) {
 	UnregisterEventData(TestEventRegisterType)
 }
 
+func TestRegisteredEventTypes(t *testing.T) {
+	RegisterEventData(TestEventRegisterType, func() EventData {
+		return &TestEventRegisterData{}
+	})
+
+	types := RegisteredEventTypes()
+	if !containsEventType(types, TestEventRegisterType) {
+		t.Error("the event type should be listed:", types)
+	}
+	for i := 1; i < len(types); i++ {
+		if types[i-1] >= types[i] {
+			t.Error("the event types should be sorted:", types)
+		}
+	}
+
+	UnregisterEventData(TestEventRegisterType)
+	if types := RegisteredEventTypes(); containsEventType(types, TestEventRegisterType) {
+		t.Error("the unregistered event type should not be listed:", types)
+	}
+}
+
+func containsEventType(types []EventType, eventType EventType) bool {
+	for _, t := range types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRegisterEventType(t *testing.T) {
+	RegisterEventType(TestEventRegisterSampleType, &TestEventData{})
+	defer UnregisterEventData(TestEventRegisterSampleType)
+
+	data, err := CreateEventData(TestEventRegisterSampleType)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if _, ok := data.(*TestEventData); !ok {
+		t.Errorf("the event type should be correct: %T", data)
+	}
+
+	other, err := CreateEventData(TestEventRegisterSampleType)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if data == other {
+		t.Error("there should be a new instance for every call")
+	}
+
+	if err := json.Unmarshal([]byte(`{"Content":"event1"}`), data); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if !reflect.DeepEqual(data, &TestEventData{"event1"}) {
+		t.Error("the data should be correct:", data)
+	}
+
+	t.Log("register a non-pointer sample")
+	RegisterEventType(TestEventRegisterValueType, TestEventData{})
+	defer UnregisterEventData(TestEventRegisterValueType)
+	data, err = CreateEventData(TestEventRegisterValueType)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if _, ok := data.(*TestEventData); !ok {
+		t.Errorf("the event type should be correct: %T", data)
+	}
+}
+
+func TestRegisterEventTypeNilSample(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || r != "eventhorizon: attempt to register nil sample for \"TestEventRegisterNil\"" {
+			t.Error("there should have been a panic:", r)
+		}
+	}()
+	RegisterEventType(TestEventRegisterNilType, nil)
+}
+
 func TestRegisterEventEmptyName(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil || r != "eventhorizon: attempt to register empty event type" {
@@ -138,6 +217,9 @@ func TestUnregisterEventTwice(t *testing.T) {
 const (
 	TestEventType                EventType = "TestEvent"
 	TestEventRegisterType        EventType = "TestEventRegister"
+	TestEventRegisterSampleType  EventType = "TestEventRegisterSample"
+	TestEventRegisterValueType   EventType = "TestEventRegisterValue"
+	TestEventRegisterNilType     EventType = "TestEventRegisterNil"
 	TestEventRegisterEmptyType   EventType = ""
 	TestEventRegisterTwiceType   EventType = "TestEventRegisterTwice"
 	TestEventUnregisterEmptyType EventType = ""
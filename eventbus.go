@@ -32,6 +32,19 @@ func (e EventBusError) Error() string {
 	return fmt.Sprintf("%s: (%s)", e.Err, e.Event)
 }
 
+// PublishError is when an event could not be published after it was saved.
+// The events of the save are stored, the error only tells that subscribers may
+// not have received the event.
+type PublishError struct {
+	Err   error
+	Event Event
+}
+
+// Error implements the Error method of the error interface.
+func (e PublishError) Error() string {
+	return fmt.Sprintf("could not publish event: %s (%s)", e.Err, e.Event)
+}
+
 // EventBus sends published events to one of each handler type and all observers.
 // That means that if the same handler is registered on multiple nodes only one
 // of them will receive the event. In contrast all observers registered on multiple
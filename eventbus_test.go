@@ -70,3 +70,13 @@ func TestEventBusError(t *testing.T) {
 		})
 	}
 }
+
+func TestPublishError(t *testing.T) {
+	err := PublishError{
+		Err:   errors.New("some error"),
+		Event: NewEvent("some event type", nil, time.Time{}),
+	}
+	if err.Error() != "could not publish event: some error (some event type@0)" {
+		t.Error("the error text should be correct:", err.Error())
+	}
+}
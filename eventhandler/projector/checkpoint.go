@@ -0,0 +1,64 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projector
+
+import (
+	"context"
+	"time"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// Checkpoint stores how far named projections have processed events.
+type Checkpoint interface {
+	// Save saves the version a projection has processed up to.
+	Save(ctx context.Context, projectionName string, version int64) error
+
+	// Load loads the version a projection has processed up to.
+	Load(ctx context.Context, projectionName string) (int64, error)
+}
+
+// waitPollInterval is the interval used to poll the checkpoint when waiting.
+const waitPollInterval = 10 * time.Millisecond
+
+// WaitForVersion blocks until the checkpoint of a projection has reached the
+// target version, or the context is done. It is useful for read-your-writes
+// flows and to synchronize tests without sleeping.
+func WaitForVersion(ctx context.Context, checkpoint Checkpoint, projectionName string, target int64) error {
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		version, err := checkpoint.Load(ctx, projectionName)
+		if err != nil {
+			return Error{
+				Err:       err,
+				Namespace: eh.NamespaceFromContext(ctx),
+			}
+		}
+		if version >= target {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return Error{
+				Err:       ctx.Err(),
+				Namespace: eh.NamespaceFromContext(ctx),
+			}
+		}
+	}
+}
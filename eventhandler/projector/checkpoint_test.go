@@ -0,0 +1,81 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWaitForVersion(t *testing.T) {
+	checkpoint := &testCheckpoint{versions: map[string]int64{}}
+
+	// Advance the checkpoint in the background like a running projector.
+	go func() {
+		for v := int64(1); v <= 5; v++ {
+			time.Sleep(5 * time.Millisecond)
+			checkpoint.Save(context.Background(), "projection", v)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := WaitForVersion(ctx, checkpoint, "projection", 5); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if v, _ := checkpoint.Load(ctx, "projection"); v != 5 {
+		t.Error("the checkpoint should be at the target version:", v)
+	}
+
+	t.Log("wait for a version that is never reached")
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := WaitForVersion(ctx, checkpoint, "projection", 10)
+	if pErr, ok := err.(Error); !ok || pErr.Err != context.DeadlineExceeded {
+		t.Error("there should be a deadline exceeded error:", err)
+	}
+
+	t.Log("checkpoint error")
+	checkpoint.err = errors.New("error")
+	err = WaitForVersion(context.Background(), checkpoint, "projection", 1)
+	if pErr, ok := err.(Error); !ok || pErr.Err != checkpoint.err {
+		t.Error("there should be an error named 'error':", err)
+	}
+}
+
+type testCheckpoint struct {
+	versions map[string]int64
+	mu       sync.Mutex
+	err      error
+}
+
+func (c *testCheckpoint) Save(ctx context.Context, name string, version int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions[name] = version
+	return nil
+}
+
+func (c *testCheckpoint) Load(ctx context.Context, name string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.versions[name], nil
+}
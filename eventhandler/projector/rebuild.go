@@ -0,0 +1,194 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projector
+
+import (
+	"context"
+	"sync"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// EventSource streams all events of the aggregate type in the context, in
+// aggregate and version order. It is implemented by the LoadAll method of
+// the MongoDB event store.
+type EventSource interface {
+	LoadAll(context.Context) (<-chan eh.Event, <-chan error)
+}
+
+// Projection is a projector and the repo of its read models, to be rebuilt.
+type Projection struct {
+	Projector Projector
+	Repo      eh.ReadWriteRepo
+	// Factory creates new models, as set with EventHandler.SetEntityFactory.
+	Factory func() eh.Entity
+}
+
+// RebuildState is the state of the rebuild of a projection.
+type RebuildState int
+
+const (
+	// RebuildPending is when the projection is waiting for a worker.
+	RebuildPending RebuildState = iota
+	// RebuildRunning is when the projection is being rebuilt.
+	RebuildRunning
+	// RebuildDone is when the projection has been rebuilt.
+	RebuildDone
+	// RebuildFailed is when the rebuild of the projection failed.
+	RebuildFailed
+)
+
+// RebuildStatus is the status of the rebuild of a projection.
+type RebuildStatus struct {
+	// Type is the type of the projector.
+	Type Type
+	// State is the state of the rebuild.
+	State RebuildState
+	// Events is the number of events projected so far.
+	Events int
+	// Err is the error of a failed rebuild.
+	Err error
+}
+
+// Rebuilder rebuilds projections from all events of a set of aggregate types,
+// using a bounded number of workers. A failing projection does not affect the
+// rebuild of the others.
+type Rebuilder struct {
+	source         EventSource
+	aggregateTypes []eh.AggregateType
+	workers        int
+
+	statuses []RebuildStatus
+	mu       sync.RWMutex
+}
+
+// NewRebuilder creates a new Rebuilder that replays the events of the
+// aggregate types from the source, with up to workers rebuilds at a time.
+func NewRebuilder(source EventSource, aggregateTypes []eh.AggregateType, workers int) *Rebuilder {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Rebuilder{
+		source:         source,
+		aggregateTypes: aggregateTypes,
+		workers:        workers,
+	}
+}
+
+// Rebuild removes all models from the repos of the projections and projects
+// all events onto them again. It blocks until all projections are done and
+// returns their statuses, in the same order as the projections.
+func (r *Rebuilder) Rebuild(ctx context.Context, projections []Projection) []RebuildStatus {
+	r.mu.Lock()
+	r.statuses = make([]RebuildStatus, len(projections))
+	for i, p := range projections {
+		r.statuses[i] = RebuildStatus{Type: p.Projector.ProjectorType()}
+	}
+	r.mu.Unlock()
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < r.workers && w < len(projections); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				r.rebuild(ctx, i, projections[i])
+			}
+		}()
+	}
+	for i := range projections {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return r.Status()
+}
+
+// Status returns the statuses of the projections of the latest rebuild, and
+// can be used to follow a rebuild while it is running.
+func (r *Rebuilder) Status() []RebuildStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]RebuildStatus, len(r.statuses))
+	copy(statuses, r.statuses)
+	return statuses
+}
+
+// rebuild rebuilds a single projection and records its status.
+func (r *Rebuilder) rebuild(ctx context.Context, i int, p Projection) {
+	r.update(i, func(s *RebuildStatus) { s.State = RebuildRunning })
+
+	err := r.project(ctx, i, p)
+
+	r.update(i, func(s *RebuildStatus) {
+		if err != nil {
+			s.State = RebuildFailed
+			s.Err = err
+			return
+		}
+		s.State = RebuildDone
+	})
+}
+
+// project clears the repo of the projection and projects all events on it.
+func (r *Rebuilder) project(ctx context.Context, i int, p Projection) error {
+	entities, err := p.Repo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		if err := p.Repo.Remove(ctx, entity.EntityID()); err != nil {
+			return err
+		}
+	}
+
+	handler := NewEventHandler(p.Projector, p.Repo)
+	handler.SetEntityFactory(p.Factory)
+
+	for _, aggregateType := range r.aggregateTypes {
+		if err := r.projectAggregateType(ctx, i, handler, aggregateType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectAggregateType projects all events of an aggregate type.
+func (r *Rebuilder) projectAggregateType(ctx context.Context, i int, handler *EventHandler, aggregateType eh.AggregateType) error {
+	// Cancel the stream if the projection fails before it has been drained.
+	ctx, cancel := context.WithCancel(eh.NewContextWithNamespaceAndType(ctx,
+		eh.NamespaceFromContext(ctx), string(aggregateType)))
+	defer cancel()
+
+	events, errs := r.source.LoadAll(ctx)
+	for event := range events {
+		if err := handler.HandleEvent(ctx, event); err != nil {
+			return err
+		}
+		r.update(i, func(s *RebuildStatus) { s.Events++ })
+	}
+	return <-errs
+}
+
+// update updates the status of a projection.
+func (r *Rebuilder) update(i int, fn func(*RebuildStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fn(&r.statuses[i])
+}
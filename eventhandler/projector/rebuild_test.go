@@ -0,0 +1,202 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package projector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/mocks"
+	"github.com/firawe/eventhorizon/repo/memory"
+	"github.com/google/uuid"
+)
+
+func TestRebuilder(t *testing.T) {
+	ctx := context.Background()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	id1, id2 := uuid.New().String(), uuid.New().String()
+	source := &testEventSource{events: map[string][]eh.Event{
+		string(mocks.AggregateType): {
+			eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+				timestamp, mocks.AggregateType, id1, 1),
+			eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event2"},
+				timestamp, mocks.AggregateType, id1, 2),
+			eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event3"},
+				timestamp, mocks.AggregateType, id2, 1),
+		},
+	}}
+
+	// A stale model that should be removed by the rebuild.
+	staleRepo := memory.NewRepo()
+	if err := staleRepo.Save(ctx, &mocks.Model{ID: "stale"}); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	errProject := errors.New("project error")
+	running := &testRunning{}
+	projections := []Projection{
+		{Projector: &testModelProjector{projectorType: "first", running: running}, Repo: staleRepo},
+		{Projector: &testModelProjector{projectorType: "failing", err: errProject}, Repo: memory.NewRepo()},
+		{Projector: &testModelProjector{projectorType: "second", running: running}, Repo: memory.NewRepo()},
+		{Projector: &testModelProjector{projectorType: "third", running: running}, Repo: memory.NewRepo()},
+	}
+	for i := range projections {
+		projections[i].Factory = func() eh.Entity { return &mocks.Model{} }
+	}
+
+	rebuilder := NewRebuilder(source, []eh.AggregateType{mocks.AggregateType}, 2)
+	statuses := rebuilder.Rebuild(ctx, projections)
+	if len(statuses) != 4 {
+		t.Fatal("there should be a status per projection:", statuses)
+	}
+
+	t.Log("the failing projection should not affect the others")
+	for _, i := range []int{0, 2, 3} {
+		s := statuses[i]
+		if s.Type != projections[i].Projector.ProjectorType() {
+			t.Error("the status should be for the projection:", s.Type)
+		}
+		if s.State != RebuildDone || s.Err != nil || s.Events != 3 {
+			t.Error("the projection should be rebuilt:", s)
+		}
+		models, err := projections[i].Repo.FindAll(ctx)
+		if err != nil {
+			t.Error("there should be no error:", err)
+		}
+		if len(models) != 2 {
+			t.Error("there should be two models:", models)
+		}
+		model, err := projections[i].Repo.Find(ctx, id1)
+		if err != nil {
+			t.Error("there should be no error:", err)
+		}
+		if m, ok := model.(*mocks.Model); !ok || m.Version != 2 || m.Content != "event2" {
+			t.Error("the model should be projected from all events:", model)
+		}
+	}
+	if _, err := staleRepo.Find(ctx, "stale"); err == nil {
+		t.Error("the stale model should be removed")
+	}
+
+	failed := statuses[1]
+	if failed.State != RebuildFailed || failed.Events != 0 {
+		t.Error("the projection should have failed:", failed)
+	}
+	if pErr, ok := failed.Err.(Error); !ok || pErr.Err != errProject {
+		t.Error("there should be a project error:", failed.Err)
+	}
+
+	if max := running.maxConcurrent(); max > 2 {
+		t.Error("there should be at most two concurrent rebuilds:", max)
+	}
+
+	t.Log("source error")
+	source.err = errors.New("source error")
+	statuses = rebuilder.Rebuild(ctx, projections[:1])
+	if statuses[0].State != RebuildFailed || statuses[0].Err != source.err {
+		t.Error("there should be a source error:", statuses[0])
+	}
+	if s := rebuilder.Status(); len(s) != 1 || s[0].Err != source.err {
+		t.Error("the status should be from the latest rebuild:", s)
+	}
+}
+
+type testModelProjector struct {
+	projectorType Type
+	running       *testRunning
+	err           error
+}
+
+func (p *testModelProjector) ProjectorType() Type {
+	return p.projectorType
+}
+
+func (p *testModelProjector) Project(ctx context.Context, event eh.Event, entity eh.Entity) (eh.Entity, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.running.enter()
+	defer p.running.leave()
+
+	model := entity.(*mocks.Model)
+	model.ID = event.AggregateID()
+	model.Version = event.Version()
+	model.Content = event.Data().(*mocks.EventData).Content
+	return model, nil
+}
+
+type testEventSource struct {
+	events map[string][]eh.Event
+	err    error
+}
+
+func (s *testEventSource) LoadAll(ctx context.Context) (<-chan eh.Event, <-chan error) {
+	events := make(chan eh.Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+
+		if s.err != nil {
+			errs <- s.err
+			return
+		}
+		for _, event := range s.events[eh.AggregateTypeFromContext(ctx)] {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// testRunning tracks the max number of concurrent projections.
+type testRunning struct {
+	running int
+	max     int
+	mu      sync.Mutex
+}
+
+func (r *testRunning) enter() {
+	r.mu.Lock()
+	r.running++
+	if r.running > r.max {
+		r.max = r.running
+	}
+	r.mu.Unlock()
+
+	// Give other rebuilds a chance to run concurrently.
+	time.Sleep(time.Millisecond)
+}
+
+func (r *testRunning) leave() {
+	r.mu.Lock()
+	r.running--
+	r.mu.Unlock()
+}
+
+func (r *testRunning) maxConcurrent() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.max
+}
@@ -29,11 +29,22 @@ type EventStoreError struct {
 	Namespace string
 	// AggregateType
 	AggregateType string
+	// ExpectedVersion is the version the save expected the aggregate to have,
+	// set for version conflicts.
+	ExpectedVersion int
+	// ActualVersion is the version of the aggregate in the store, set for
+	// version conflicts. It is -1 if the version could not be read.
+	ActualVersion int
+	// EventType is the type of the event that failed, if any.
+	EventType EventType
 }
 
 // Error implements the Error method of the errors.Error interface.
 func (e EventStoreError) Error() string {
 	errStr := e.Err.Error()
+	if e.EventType != "" {
+		errStr += " " + string(e.EventType)
+	}
 	if e.BaseErr != nil {
 		errStr += ": " + e.BaseErr.Error()
 	}
@@ -54,7 +65,8 @@ type EventStore interface {
 	// Save appends all events in the event stream to the store.
 	Save(ctx context.Context, events []Event, originalVersion int) error
 
-	// Load loads all events for the aggregate id from the store.
+	// Load loads all events for the aggregate id from the store. Returns a
+	// EventStoreError with ErrAggregateNotFound if there is no aggregate.
 	Load(context.Context, string) ([]Event, context.Context, error)
 }
 
@@ -67,6 +79,7 @@ type EventStoreMaintainer interface {
 	// Returns ErrAggregateNotFound if there is no aggregate.
 	Replace(context.Context, Event) error
 
-	// RenameEvent renames all instances of the event type.
-	RenameEvent(ctx context.Context, from, to EventType) error
+	// RenameEvent renames all instances of the event type and returns the
+	// number of renamed events.
+	RenameEvent(ctx context.Context, from, to EventType) (int, error)
 }
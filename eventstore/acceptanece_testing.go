@@ -16,6 +16,8 @@ package eventstore
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -65,6 +67,22 @@ func AcceptanceTest(t *testing.T, ctx context.Context, store eh.EventStore) []eh
 		t.Error("there should be a ErrIncerrectEventVersion error:", err)
 	}
 
+	t.Log("try to save to a missing aggregate")
+	eventMissing := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event2"},
+		timestamp, mocks.AggregateType, uuid.New().String(), 2)
+	err = store.Save(ctx, []eh.Event{eventMissing}, 1)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrIncorrectEventVersion {
+		t.Error("there should be a ErrIncorrectEventVersion error:", err)
+	}
+
+	t.Log("try to create an aggregate above version 1")
+	eventAbove := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event5"},
+		timestamp, mocks.AggregateType, uuid.New().String(), 5)
+	err = store.Save(ctx, []eh.Event{eventAbove}, 0)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrIncorrectEventVersion {
+		t.Error("there should be a ErrIncorrectEventVersion error:", err)
+	}
+
 	t.Log("save event, version 2")
 	event2 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event2"},
 		timestamp, mocks.AggregateType, id.String(), 2)
@@ -108,8 +126,8 @@ func AcceptanceTest(t *testing.T, ctx context.Context, store eh.EventStore) []eh
 
 	t.Log("load events for non-existing aggregate")
 	events, ctx, err := store.Load(ctx, uuid.New().String())
-	if err != nil {
-		t.Error("there should be no error:", err)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrAggregateNotFound {
+		t.Error("there should be a ErrAggregateNotFound error:", err)
 	}
 	if len(events) != 0 {
 		t.Error("there should be no loaded events:", eventsToString(events))
@@ -135,6 +153,30 @@ func AcceptanceTest(t *testing.T, ctx context.Context, store eh.EventStore) []eh
 		}
 	}
 
+	t.Log("load events with load options")
+	for _, tc := range []struct {
+		opts     eh.LoadOptions
+		versions []int
+	}{
+		{eh.LoadOptions{MinVersion: 3}, []int{3, 4, 5, 6}},
+		{eh.LoadOptions{MaxVersion: 2}, []int{1, 2}},
+		{eh.LoadOptions{MinVersion: 2, MaxVersion: 5, Limit: 2}, []int{2, 3}},
+		{eh.LoadOptions{Sort: eh.LoadSortDescending, Limit: 2}, []int{6, 5}},
+		{eh.LoadOptions{MinVersion: 7}, []int{}},
+	} {
+		events, _, err := store.Load(eh.NewContextWithLoadOptions(ctx, tc.opts), id.String())
+		if err != nil {
+			t.Error("there should be no error:", tc.opts, err)
+		}
+		versions := []int{}
+		for _, event := range events {
+			versions = append(versions, event.Version())
+		}
+		if !reflect.DeepEqual(versions, tc.versions) {
+			t.Error("the loaded versions should be correct:", tc.opts, versions)
+		}
+	}
+
 	t.Log("load events for another aggregate")
 	events, ctx, err = store.Load(ctx, id2.String())
 	if err != nil {
@@ -150,6 +192,84 @@ func AcceptanceTest(t *testing.T, ctx context.Context, store eh.EventStore) []eh
 		}
 	}
 
+	t.Log("save events concurrently at the same version")
+	const concurrentSaves = 10
+	concurrentEvents := make([]eh.Event, concurrentSaves)
+	errs := make(chan error, concurrentSaves)
+	start := make(chan struct{})
+	for i := range concurrentEvents {
+		concurrentEvents[i] = eh.NewEventForAggregate(mocks.EventType,
+			&mocks.EventData{Content: fmt.Sprintf("concurrent%d", i)},
+			timestamp, mocks.AggregateType, id2.String(), 2)
+		go func(event eh.Event) {
+			<-start
+			errs <- store.Save(ctx, []eh.Event{event}, 1)
+		}(concurrentEvents[i])
+	}
+	close(start)
+	saved := 0
+	for range concurrentEvents {
+		err := <-errs
+		if err == nil {
+			saved++
+		} else if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrIncorrectEventVersion {
+			t.Error("there should be a ErrIncorrectEventVersion error:", err)
+		}
+	}
+	if saved != 1 {
+		t.Error("only one concurrent save should succeed:", saved)
+	}
+	events, _, err = store.Load(ctx, id2.String())
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(events) != 2 {
+		t.Fatal("there should be two events:", eventsToString(events))
+	}
+	for _, event := range concurrentEvents {
+		if mocks.CompareEvents(events[1], event) == nil {
+			savedEvents = append(savedEvents, event)
+		}
+	}
+
+	t.Log("create an aggregate concurrently")
+	id3 := uuid.New().String()
+	start = make(chan struct{})
+	for i := range concurrentEvents {
+		concurrentEvents[i] = eh.NewEventForAggregate(mocks.EventType,
+			&mocks.EventData{Content: fmt.Sprintf("create%d", i)},
+			timestamp, mocks.AggregateType, id3, 1)
+		go func(event eh.Event) {
+			<-start
+			errs <- store.Save(ctx, []eh.Event{event}, 0)
+		}(concurrentEvents[i])
+	}
+	close(start)
+	saved = 0
+	for range concurrentEvents {
+		err := <-errs
+		if err == nil {
+			saved++
+		} else if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrIncorrectEventVersion {
+			t.Error("there should be a ErrIncorrectEventVersion error:", err)
+		}
+	}
+	if saved != 1 {
+		t.Error("only one concurrent create should succeed:", saved)
+	}
+	events, _, err = store.Load(ctx, id3)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(events) != 1 {
+		t.Fatal("there should be one event:", eventsToString(events))
+	}
+	for _, event := range concurrentEvents {
+		if mocks.CompareEvents(events[0], event) == nil {
+			savedEvents = append(savedEvents, event)
+		}
+	}
+
 	return savedEvents
 }
 
@@ -234,9 +354,13 @@ func MaintainerAcceptanceTest(t *testing.T, ctx context.Context, store eh.EventS
 
 	t.Log("rename events to the new type")
 	newEventType := eh.EventType("new_event_type")
-	if err := store.RenameEvent(ctx, oldEventType, newEventType); err != nil {
+	n, err := store.RenameEvent(ctx, oldEventType, newEventType)
+	if err != nil {
 		t.Error("there should be no error:", err)
 	}
+	if n != 2 {
+		t.Error("there should be two renamed events:", n)
+	}
 	events, ctx, err = store.Load(ctx, id1)
 	if err != nil {
 		t.Error("there should be no error:", err)
@@ -261,6 +385,92 @@ func MaintainerAcceptanceTest(t *testing.T, ctx context.Context, store eh.EventS
 	if err := mocks.CompareEvents(events[0], newEvent2); err != nil {
 		t.Error("the event was incorrect:", err)
 	}
+
+	t.Log("rename events of a type without events")
+	n, err = store.RenameEvent(ctx, oldEventType, newEventType)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if n != 0 {
+		t.Error("there should be no renamed events:", n)
+	}
+}
+
+// ClearableEventStore is an EventStore that can clear the aggregates of the
+// aggregate type in the context.
+type ClearableEventStore interface {
+	eh.EventStore
+
+	// Clear removes the aggregates of the aggregate type in the namespace of
+	// the context.
+	Clear(context.Context) error
+}
+
+// ClearAcceptanceTest is the acceptance test that all implementations of
+// EventStore with a Clear method scoped to the aggregate type should pass. It
+// should manually be called from a test case in each implementation:
+//
+//   func TestClear(t *testing.T) {
+//       ctx := context.Background() // Or other when testing namespaces.
+//       store := NewEventStore()
+//       eventstore.ClearAcceptanceTest(t, ctx, store)
+//   }
+//
+func ClearAcceptanceTest(t *testing.T, ctx context.Context, store ClearableEventStore) {
+	ns := eh.NamespaceFromContext(ctx)
+	otherType := eh.AggregateType("OtherAggregate")
+	typeCtx := eh.NewScopedContext(ctx, ns, mocks.AggregateType)
+	otherCtx := eh.NewScopedContext(ctx, ns, otherType)
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	t.Log("save events of two aggregate types")
+	id := uuid.New().String()
+	event1 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+	event2 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event2"},
+		timestamp, mocks.AggregateType, id, 2)
+	if err := store.Save(typeCtx, []eh.Event{event1, event2}, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	otherID := uuid.New().String()
+	otherEvent := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "other"},
+		timestamp, otherType, otherID, 1)
+	if err := store.Save(otherCtx, []eh.Event{otherEvent}, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	t.Log("append at the version of the last event")
+	event3 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event3"},
+		timestamp, mocks.AggregateType, id, 3)
+	if err := store.Save(typeCtx, []eh.Event{event3}, 2); err != nil {
+		t.Error("there should be no error:", err)
+	}
+
+	t.Log("clear only the aggregate type of the context")
+	if err := store.Clear(typeCtx); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	_, _, err := store.Load(typeCtx, id)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrAggregateNotFound {
+		t.Error("there should be a ErrAggregateNotFound error:", err)
+	}
+	events, _, err := store.Load(otherCtx, otherID)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(events) != 1 {
+		t.Error("the other aggregate type should be kept:", eventsToString(events))
+	}
+
+	t.Log("clear an already cleared aggregate type")
+	if err := store.Clear(typeCtx); err != nil {
+		t.Error("there should be no error:", err)
+	}
+
+	t.Log("save again after clearing")
+	if err := store.Save(typeCtx, []eh.Event{event1}, 0); err != nil {
+		t.Error("there should be no error:", err)
+	}
 }
 
 func eventsToString(events []eh.Event) string {
@@ -0,0 +1,121 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// ErrCouldNotAudit is when an audit record could not be written to the sink.
+var ErrCouldNotAudit = errors.New("could not audit event")
+
+// Record is an audit record of a saved event.
+type Record struct {
+	Namespace     string
+	AggregateType eh.AggregateType
+	AggregateID   string
+	EventID       string
+	EventType     eh.EventType
+	Version       int
+	Timestamp     time.Time
+	// Actor is the actor set in the context with NewContextWithActor.
+	Actor string
+}
+
+// Sink is an audit sink that writes audit records, for example to an
+// immutable log.
+type Sink interface {
+	// Audit writes an audit record.
+	Audit(context.Context, Record) error
+}
+
+// EventStore wraps an EventStore and writes an audit record for every
+// saved event.
+type EventStore struct {
+	eh.EventStore
+	sink       Sink
+	bestEffort bool
+}
+
+// NewEventStore creates a new EventStore. Failures to audit are returned
+// from Save by default, see SetBestEffort.
+func NewEventStore(eventStore eh.EventStore, sink Sink) *EventStore {
+	if eventStore == nil || sink == nil {
+		return nil
+	}
+
+	return &EventStore{
+		EventStore: eventStore,
+		sink:       sink,
+	}
+}
+
+// SetBestEffort sets if failures to audit should be ignored instead of being
+// returned from Save. The events are saved in both cases.
+func (s *EventStore) SetBestEffort(bestEffort bool) {
+	s.bestEffort = bestEffort
+}
+
+// Save implements the Save method of the eventhorizon.EventStore interface.
+func (s *EventStore) Save(ctx context.Context, events []eh.Event, originalVersion int) error {
+	if err := s.EventStore.Save(ctx, events, originalVersion); err != nil {
+		return err
+	}
+
+	// Only audit events that are successfully saved.
+	for _, event := range events {
+		record := Record{
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: event.AggregateType(),
+			AggregateID:   event.AggregateID(),
+			EventID:       event.ID(),
+			EventType:     event.EventType(),
+			Version:       event.Version(),
+			Timestamp:     event.Timestamp(),
+			Actor:         ActorFromContext(ctx),
+		}
+		if err := s.sink.Audit(ctx, record); err != nil && !s.bestEffort {
+			return eh.EventStoreError{
+				Err:           ErrCouldNotAudit,
+				BaseErr:       err,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+	}
+
+	return nil
+}
+
+type contextKey int
+
+const actorKey contextKey = iota
+
+// ActorFromContext returns the actor from the context, or an empty string.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok {
+		return actor
+	}
+	return ""
+}
+
+// NewContextWithActor sets the actor to use in audit records in the context.
+func NewContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/eventstore"
+	"github.com/firawe/eventhorizon/eventstore/memory"
+	"github.com/firawe/eventhorizon/mocks"
+	"github.com/google/uuid"
+)
+
+func TestEventStore(t *testing.T) {
+	sink := &testSink{}
+	store := NewEventStore(memory.NewEventStore(), sink)
+	if store == nil {
+		t.Fatal("there should be a store")
+	}
+
+	// Run the actual test suite.
+	savedEvents := eventstore.AcceptanceTest(t, context.Background(), store)
+	if len(sink.records) != len(savedEvents) {
+		t.Error("there should be an audit record for each saved event:", len(sink.records))
+	}
+
+	// And then some more audit specific testing.
+	sink.records = nil
+	ctx := NewContextWithActor(context.Background(), "user")
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event1 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+	event2 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event2"},
+		timestamp, mocks.AggregateType, id, 2)
+	if err := store.Save(ctx, []eh.Event{event1, event2}, 0); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	expected := []Record{
+		{
+			Namespace:     eh.DefaultNamespace,
+			AggregateType: mocks.AggregateType,
+			AggregateID:   id,
+			EventType:     mocks.EventType,
+			Version:       1,
+			Timestamp:     timestamp,
+			Actor:         "user",
+		},
+		{
+			Namespace:     eh.DefaultNamespace,
+			AggregateType: mocks.AggregateType,
+			AggregateID:   id,
+			EventType:     mocks.EventType,
+			Version:       2,
+			Timestamp:     timestamp,
+			Actor:         "user",
+		},
+	}
+	if !reflect.DeepEqual(sink.records, expected) {
+		t.Error("the audit records should be correct:", sink.records)
+	}
+
+	t.Log("fail to audit")
+	sink.records = nil
+	sink.err = errors.New("error")
+	event3 := eh.NewEventForAggregate(mocks.EventType, nil, timestamp, mocks.AggregateType, id, 3)
+	err := store.Save(ctx, []eh.Event{event3}, 2)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != ErrCouldNotAudit || esErr.BaseErr != sink.err {
+		t.Error("there should be a ErrCouldNotAudit error:", err)
+	}
+
+	t.Log("fail to audit with best effort")
+	store.SetBestEffort(true)
+	event4 := eh.NewEventForAggregate(mocks.EventType, nil, timestamp, mocks.AggregateType, id, 4)
+	if err := store.Save(ctx, []eh.Event{event4}, 3); err != nil {
+		t.Error("there should be no error:", err)
+	}
+
+	t.Log("don't audit failed saves")
+	sink.err = nil
+	if err := store.Save(ctx, []eh.Event{event1}, 4); err == nil {
+		t.Error("there should be an error")
+	}
+	if len(sink.records) != 0 {
+		t.Error("there should be no audit records:", sink.records)
+	}
+}
+
+type testSink struct {
+	records []Record
+	err     error
+}
+
+func (s *testSink) Audit(ctx context.Context, r Record) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, r)
+	return nil
+}
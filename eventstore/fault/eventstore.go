@@ -0,0 +1,107 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fault
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// ErrInjected is the default error of injected failures.
+var ErrInjected = errors.New("injected failure")
+
+// Policy is the policy for which operations to fail, and with what error.
+type Policy struct {
+	// Sequence scripts the results of the first operations in order, a nil
+	// error lets the operation through. Rate is used after the sequence.
+	Sequence []error
+	// Rate is the fraction of operations to fail, from 0 to 1.
+	Rate float64
+	// Err is the error of failures by Rate, defaults to ErrInjected.
+	Err error
+	// Seed seeds the failures by Rate, to make them repeatable.
+	Seed int64
+}
+
+// EventStore wraps an EventStore and fails operations according to a
+// policy, for testing the resilience of the code using the store. Failed
+// operations are not passed on to the wrapped store.
+type EventStore struct {
+	eh.EventStore
+	policy Policy
+	rand   *rand.Rand
+	ops    int
+	mu     sync.Mutex
+}
+
+// NewEventStore creates a new EventStore.
+func NewEventStore(eventStore eh.EventStore, policy Policy) *EventStore {
+	if eventStore == nil {
+		return nil
+	}
+	if policy.Err == nil {
+		policy.Err = ErrInjected
+	}
+
+	return &EventStore{
+		EventStore: eventStore,
+		policy:     policy,
+		rand:       rand.New(rand.NewSource(policy.Seed)),
+	}
+}
+
+// Save implements the Save method of the eventhorizon.EventStore interface.
+func (s *EventStore) Save(ctx context.Context, events []eh.Event, originalVersion int) error {
+	if err := s.fault(); err != nil {
+		return err
+	}
+	return s.EventStore.Save(ctx, events, originalVersion)
+}
+
+// Load implements the Load method of the eventhorizon.EventStore interface.
+func (s *EventStore) Load(ctx context.Context, id string) ([]eh.Event, context.Context, error) {
+	if err := s.fault(); err != nil {
+		return nil, ctx, err
+	}
+	return s.EventStore.Load(ctx, id)
+}
+
+// Operations returns the number of operations so far, failed or not.
+func (s *EventStore) Operations() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ops
+}
+
+// fault returns the error to fail the next operation with, or nil.
+func (s *EventStore) fault() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op := s.ops
+	s.ops++
+	if op < len(s.policy.Sequence) {
+		return s.policy.Sequence[op]
+	}
+	if s.policy.Rate > 0 && s.rand.Float64() < s.policy.Rate {
+		return s.policy.Err
+	}
+	return nil
+}
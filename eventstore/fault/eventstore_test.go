@@ -0,0 +1,207 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fault
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/aggregatestore/events"
+	"github.com/firawe/eventhorizon/eventstore"
+	"github.com/firawe/eventhorizon/eventstore/memory"
+	"github.com/firawe/eventhorizon/mocks"
+	"github.com/google/uuid"
+)
+
+func TestEventStore(t *testing.T) {
+	if store := NewEventStore(nil, Policy{}); store != nil {
+		t.Error("there should be no store:", store)
+	}
+
+	store := NewEventStore(memory.NewEventStore(), Policy{})
+	if store == nil {
+		t.Fatal("there should be a store")
+	}
+
+	// Run the actual test suite, without any faults.
+	eventstore.AcceptanceTest(t, context.Background(), store)
+}
+
+func TestSequence(t *testing.T) {
+	errSave := errors.New("save error")
+	errLoad := errors.New("load error")
+	baseStore := memory.NewEventStore()
+	store := NewEventStore(baseStore, Policy{
+		Sequence: []error{errSave, nil, errLoad, nil},
+	})
+
+	ctx := context.Background()
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event1 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+
+	t.Log("failed save")
+	if err := store.Save(ctx, []eh.Event{event1}, 0); err != errSave {
+		t.Error("there should be a save error:", err)
+	}
+	if events, _, _ := baseStore.Load(ctx, id); len(events) != 0 {
+		t.Error("the failed save should not reach the store:", events)
+	}
+
+	t.Log("retried save")
+	if err := store.Save(ctx, []eh.Event{event1}, 0); err != nil {
+		t.Error("there should be no error:", err)
+	}
+
+	t.Log("failed load")
+	if _, _, err := store.Load(ctx, id); err != errLoad {
+		t.Error("there should be a load error:", err)
+	}
+
+	t.Log("retried load")
+	events, _, err := store.Load(ctx, id)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(events) != 1 {
+		t.Error("there should be one event loaded:", events)
+	}
+
+	t.Log("let operations through after the sequence")
+	if _, _, err := store.Load(ctx, id); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if ops := store.Operations(); ops != 5 {
+		t.Error("the number of operations should be correct:", ops)
+	}
+}
+
+func TestAggregateStore(t *testing.T) {
+	errSave := errors.New("save error")
+	errLoad := errors.New("load error")
+	store := NewEventStore(memory.NewEventStore(), Policy{
+		Sequence: []error{errSave, nil, errLoad},
+	})
+	aggregateStore, err := events.NewAggregateStore(store, &mocks.EventBus{})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	ctx := context.Background()
+	id := uuid.New().String()
+	agg := &TestAggregate{AggregateBase: events.NewAggregateBase(TestAggregateType, id)}
+	agg.StoreEvent(mocks.EventType, &mocks.EventData{Content: "event1"}, time.Now())
+
+	t.Log("failed save keeps the uncommitted events")
+	if err := aggregateStore.Save(ctx, agg); err != errSave {
+		t.Error("there should be a save error:", err)
+	}
+	if len(agg.Events()) != 1 {
+		t.Error("the events should still be uncommitted:", agg.Events())
+	}
+	if err := aggregateStore.Save(ctx, agg); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(agg.Events()) != 0 {
+		t.Error("the events should be committed:", agg.Events())
+	}
+
+	t.Log("failed load")
+	if _, err := aggregateStore.Load(ctx, TestAggregateType, id); err != errLoad {
+		t.Error("there should be a load error:", err)
+	}
+	loaded, err := aggregateStore.Load(ctx, TestAggregateType, id)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if v := loaded.(events.Aggregate).Version(); v != 1 {
+		t.Error("the version should be 1:", v)
+	}
+}
+
+func TestRate(t *testing.T) {
+	ctx := context.Background()
+	failures := func(policy Policy) []bool {
+		store := NewEventStore(memory.NewEventStore(), policy)
+		var failed []bool
+		for i := 0; i < 100; i++ {
+			_, _, err := store.Load(ctx, uuid.New().String())
+			failed = append(failed, err == store.policy.Err)
+		}
+		return failed
+	}
+
+	t.Log("repeatable failures with the same seed")
+	first := failures(Policy{Rate: 0.3, Seed: 42})
+	second := failures(Policy{Rate: 0.3, Seed: 42})
+	n := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatal("the failures should be the same for the same seed:", i)
+		}
+		if first[i] {
+			n++
+		}
+	}
+	if n == 0 || n == 100 {
+		t.Error("some operations should fail:", n)
+	}
+
+	t.Log("fail all operations with a custom error")
+	errDown := errors.New("down")
+	for i, failed := range failures(Policy{Rate: 1, Err: errDown}) {
+		if !failed {
+			t.Error("the operation should fail:", i)
+		}
+	}
+
+	t.Log("default error")
+	store := NewEventStore(memory.NewEventStore(), Policy{Rate: 1})
+	if _, _, err := store.Load(ctx, uuid.New().String()); err != ErrInjected {
+		t.Error("there should be a ErrInjected error:", err)
+	}
+}
+
+func init() {
+	eh.RegisterAggregate(func(id string) eh.Aggregate {
+		return &TestAggregate{AggregateBase: events.NewAggregateBase(TestAggregateType, id)}
+	})
+}
+
+const TestAggregateType eh.AggregateType = "FaultTestAggregate"
+
+type TestAggregate struct {
+	*events.AggregateBase
+}
+
+func (a *TestAggregate) HandleCommand(ctx context.Context, cmd eh.Command) error {
+	return nil
+}
+
+func (a *TestAggregate) ApplyEvent(ctx context.Context, event eh.Event) error {
+	return nil
+}
+
+func (a *TestAggregate) Data() events.AggregateData {
+	return nil
+}
+
+func (a *TestAggregate) ApplySnapshot(context.Context, eh.Snapshot) error {
+	return nil
+}
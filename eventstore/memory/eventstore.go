@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -86,9 +87,19 @@ func (s *EventStore) Save(ctx context.Context, events []eh.Event, originalVersio
 
 	// Either insert a new aggregate or append to an existing.
 	if originalVersion == 0 {
+		// Only insert if the aggregate does not exist.
+		if _, ok := s.db[ns][aggregateID]; ok {
+			return eh.EventStoreError{
+				BaseErr:   fmt.Errorf("aggregate %s already exists", aggregateID),
+				Err:       eh.ErrIncorrectEventVersion,
+				Namespace: eh.NamespaceFromContext(ctx),
+			}
+		}
+
+		// Record the version of the last event, like the MongoDB store.
 		aggregate := aggregateRecord{
 			AggregateID: aggregateID,
-			Version:     len(dbEvents),
+			Version:     dbEvents[len(dbEvents)-1].Version,
 			Events:      dbEvents,
 		}
 
@@ -97,19 +108,19 @@ func (s *EventStore) Save(ctx context.Context, events []eh.Event, originalVersio
 		// Increment aggregate version on insert of new event record, and
 		// only insert if version of aggregate is matching (ie not changed
 		// since loading the aggregate).
-		if aggregate, ok := s.db[ns][aggregateID]; ok {
-			if aggregate.Version != originalVersion {
-				return eh.EventStoreError{
-					Err:       ErrCouldNotSaveAggregate,
-					Namespace: eh.NamespaceFromContext(ctx),
-				}
+		aggregate, ok := s.db[ns][aggregateID]
+		if !ok || aggregate.Version != originalVersion {
+			return eh.EventStoreError{
+				BaseErr:   fmt.Errorf("aggregate %s not found at version %d", aggregateID, originalVersion),
+				Err:       eh.ErrIncorrectEventVersion,
+				Namespace: eh.NamespaceFromContext(ctx),
 			}
+		}
 
-			aggregate.Version += len(dbEvents)
-			aggregate.Events = append(aggregate.Events, dbEvents...)
+		aggregate.Version = dbEvents[len(dbEvents)-1].Version
+		aggregate.Events = append(aggregate.Events, dbEvents...)
 
-			s.db[ns][aggregateID] = aggregate
-		}
+		s.db[ns][aggregateID] = aggregate
 	}
 
 	return nil
@@ -127,12 +138,29 @@ func (s *EventStore) Load(ctx context.Context, id string) ([]eh.Event, context.C
 
 	aggregate, ok := s.db[ns][id]
 	if !ok {
-		return []eh.Event{}, ctx, nil
+		return []eh.Event{}, ctx, eh.EventStoreError{
+			Err:           eh.ErrAggregateNotFound,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
 	}
 
-	events := make([]eh.Event, len(aggregate.Events))
-	for i, dbEvent := range aggregate.Events {
-		events[i] = event{dbEvent: dbEvent}
+	opts, _ := eh.LoadOptionsFromContext(ctx)
+	events := []eh.Event{}
+	for _, dbEvent := range aggregate.Events {
+		if dbEvent.Version < opts.MinVersion ||
+			(opts.MaxVersion > 0 && dbEvent.Version > opts.MaxVersion) {
+			continue
+		}
+		events = append(events, event{dbEvent: dbEvent})
+	}
+	if opts.Sort == eh.LoadSortDescending {
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+	if opts.Limit > 0 && len(events) > opts.Limit {
+		events = events[:opts.Limit]
 	}
 
 	return events, ctx, nil
@@ -172,13 +200,14 @@ func (s *EventStore) Replace(ctx context.Context, event eh.Event) error {
 }
 
 // RenameEvent implements the RenameEvent method of the eventhorizon.EventStore interface.
-func (s *EventStore) RenameEvent(ctx context.Context, from, to eh.EventType) error {
+func (s *EventStore) RenameEvent(ctx context.Context, from, to eh.EventType) (int, error) {
 	// Ensure that the namespace exists.
 	ns := s.namespace(ctx)
 
 	s.dbMu.Lock()
 	defer s.dbMu.Unlock()
 
+	renamed := 0
 	updated := map[string]aggregateRecord{}
 	for id, aggregate := range s.db[ns] {
 		events := make([]dbEvent, len(aggregate.Events))
@@ -186,6 +215,7 @@ func (s *EventStore) RenameEvent(ctx context.Context, from, to eh.EventType) err
 			if e.EventType == from {
 				// Rename any matching event.
 				e.EventType = to
+				renamed++
 			}
 			events[i] = e
 		}
@@ -197,9 +227,93 @@ func (s *EventStore) RenameEvent(ctx context.Context, from, to eh.EventType) err
 		s.db[ns][id] = aggregate
 	}
 
+	return renamed, nil
+}
+
+// Clear clears the aggregates of the aggregate type in the context, in the
+// namespace of the context, like the MongoDB store drops the collections of
+// the aggregate type.
+func (s *EventStore) Clear(ctx context.Context) error {
+	aggregateType := eh.AggregateType(eh.AggregateTypeFromContext(ctx))
+	if aggregateType == "" {
+		return eh.EventStoreError{
+			BaseErr:   errors.New("no aggregate type"),
+			Err:       eh.ErrInvalidEvent,
+			Namespace: eh.NamespaceFromContext(ctx),
+		}
+	}
+
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+	for id, aggregate := range s.db[eh.NamespaceFromContext(ctx)] {
+		if len(aggregate.Events) > 0 && aggregate.Events[0].AggregateType == aggregateType {
+			delete(s.db[eh.NamespaceFromContext(ctx)], id)
+		}
+	}
 	return nil
 }
 
+// Close implements the Close method of the other event stores, there is
+// nothing to release for the memory store.
+func (s *EventStore) Close() {}
+
+// CopyTo copies the events of all aggregates of a type in the namespace to
+// the target store, keeping their versions, and returns the number of events
+// copied. Events that the target already has a version of are skipped, which
+// makes it safe to copy again after a failure.
+func (s *EventStore) CopyTo(ctx context.Context, target eh.EventStore, aggregateType eh.AggregateType) (int, error) {
+	// Ensure that the namespace exists.
+	ns := s.namespace(ctx)
+
+	// Collect the events first, the target may be this store.
+	s.dbMu.RLock()
+	ids := make([]string, 0, len(s.db[ns]))
+	aggregates := map[string][]eh.Event{}
+	for id, aggregate := range s.db[ns] {
+		if len(aggregate.Events) == 0 || aggregate.Events[0].AggregateType != aggregateType {
+			continue
+		}
+		events := make([]eh.Event, len(aggregate.Events))
+		for i, dbEvent := range aggregate.Events {
+			events[i] = event{dbEvent: dbEvent}
+		}
+		ids = append(ids, id)
+		aggregates[id] = events
+	}
+	s.dbMu.RUnlock()
+	sort.Strings(ids)
+
+	copied := 0
+	for _, id := range ids {
+		existing, _, err := target.Load(ctx, id)
+		if esErr, ok := err.(eh.EventStoreError); ok && esErr.Err == eh.ErrAggregateNotFound {
+			existing = nil
+		} else if err != nil {
+			return copied, err
+		}
+		version := 0
+		if len(existing) > 0 {
+			version = existing[len(existing)-1].Version()
+		}
+
+		var events []eh.Event
+		for _, e := range aggregates[id] {
+			if e.Version() > version {
+				events = append(events, e)
+			}
+		}
+		if len(events) == 0 {
+			continue
+		}
+		if err := target.Save(ctx, events, version); err != nil {
+			return copied, err
+		}
+		copied += len(events)
+	}
+
+	return copied, nil
+}
+
 // Helper to get the namespace and ensure that its data exists.
 func (s *EventStore) namespace(ctx context.Context) string {
 	s.dbMu.Lock()
@@ -16,10 +16,15 @@ package memory
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	eh "github.com/firawe/eventhorizon"
 	"github.com/firawe/eventhorizon/eventstore"
+	"github.com/firawe/eventhorizon/mocks"
 )
 
 func TestEventStore(t *testing.T) {
@@ -39,4 +44,193 @@ func TestEventStore(t *testing.T) {
 
 	t.Log("event store maintainer")
 	eventstore.MaintainerAcceptanceTest(t, context.Background(), store)
+
+	t.Log("event store clear")
+	eventstore.ClearAcceptanceTest(t, ctx, store)
+}
+
+func TestSaveExisting(t *testing.T) {
+	store := NewEventStore()
+	ctx := context.Background()
+
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event1 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+	if err := store.Save(ctx, []eh.Event{event1}, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	t.Log("do not overwrite an existing aggregate")
+	other := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "other"},
+		timestamp, mocks.AggregateType, id, 1)
+	err := store.Save(ctx, []eh.Event{other}, 0)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrIncorrectEventVersion {
+		t.Error("there should be a ErrIncorrectEventVersion error:", err)
+	}
+	events, _, err := store.Load(ctx, id)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if len(events) != 1 || !reflect.DeepEqual(events[0].Data(), event1.Data()) {
+		t.Error("the stored event should be kept:", events)
+	}
+}
+
+func TestClear(t *testing.T) {
+	store := NewEventStore()
+	defer store.Close()
+	ctx := eh.NewContextWithNamespaceAndType(context.Background(), "ns", string(mocks.AggregateType))
+	otherCtx := eh.NewContextWithNamespaceAndType(context.Background(), "other", string(mocks.AggregateType))
+
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+	for _, ctx := range []context.Context{ctx, otherCtx} {
+		if err := store.Save(ctx, []eh.Event{event}, 0); err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+	}
+
+	t.Log("clear only the namespace of the context")
+	if err := store.Clear(ctx); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	_, _, err := store.Load(ctx, id)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrAggregateNotFound {
+		t.Error("there should be a ErrAggregateNotFound error:", err)
+	}
+	if events, _, err := store.Load(otherCtx, id); err != nil || len(events) != 1 {
+		t.Error("the other namespace should be kept:", events, err)
+	}
+
+	t.Log("save again after clearing")
+	if err := store.Save(ctx, []eh.Event{event}, 0); err != nil {
+		t.Error("there should be no error:", err)
+	}
+
+	t.Log("clear without an aggregate type")
+	err = store.Clear(eh.NewContextWithNamespaceAndType(context.Background(), "ns", ""))
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrInvalidEvent {
+		t.Error("there should be a ErrInvalidEvent error:", err)
+	}
+}
+
+func TestLoadOptions(t *testing.T) {
+	store := NewEventStore()
+	ctx := context.Background()
+
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	events := []eh.Event{}
+	for v := 1; v <= 6; v++ {
+		events = append(events, eh.NewEventForAggregate(mocks.EventType,
+			&mocks.EventData{Content: "event"}, timestamp, mocks.AggregateType, id, v))
+	}
+	if err := store.Save(ctx, events, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	testCases := map[string]struct {
+		opts     eh.LoadOptions
+		versions []int
+	}{
+		"all": {
+			eh.LoadOptions{},
+			[]int{1, 2, 3, 4, 5, 6},
+		},
+		"min version": {
+			eh.LoadOptions{MinVersion: 3},
+			[]int{3, 4, 5, 6},
+		},
+		"max version": {
+			eh.LoadOptions{MaxVersion: 4},
+			[]int{1, 2, 3, 4},
+		},
+		"limit": {
+			eh.LoadOptions{MinVersion: 2, Limit: 2},
+			[]int{2, 3},
+		},
+		"descending": {
+			eh.LoadOptions{MaxVersion: 5, Limit: 3, Sort: eh.LoadSortDescending},
+			[]int{5, 4, 3},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			loaded, _, err := store.Load(eh.NewContextWithLoadOptions(ctx, tc.opts), id)
+			if err != nil {
+				t.Error("there should be no error:", err)
+			}
+			versions := []int{}
+			for _, e := range loaded {
+				versions = append(versions, e.Version())
+			}
+			if !reflect.DeepEqual(versions, tc.versions) {
+				t.Error("the loaded versions should be correct:", versions)
+			}
+		})
+	}
+}
+
+func TestCopyTo(t *testing.T) {
+	ctx := context.Background()
+	source := NewEventStore()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	newEvents := func(aggregateType eh.AggregateType, id string, n int) []eh.Event {
+		var events []eh.Event
+		for v := 1; v <= n; v++ {
+			events = append(events, eh.NewEventForAggregate(mocks.EventType,
+				&mocks.EventData{Content: "event"}, timestamp, aggregateType, id, v))
+		}
+		return events
+	}
+	id1, id2, other := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	for _, events := range [][]eh.Event{
+		newEvents(mocks.AggregateType, id1, 3),
+		newEvents(mocks.AggregateType, id2, 2),
+		newEvents("OtherAggregate", other, 2),
+	} {
+		if err := source.Save(ctx, events, 0); err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+	}
+
+	t.Log("copy to a target with some of the events")
+	target := NewEventStore()
+	if err := target.Save(ctx, newEvents(mocks.AggregateType, id1, 1), 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	n, err := source.CopyTo(ctx, target, mocks.AggregateType)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if n != 4 {
+		t.Error("the number of copied events should be correct:", n)
+	}
+	for _, id := range []string{id1, id2} {
+		expected, _, err := source.Load(ctx, id)
+		if err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		copied, _, err := target.Load(ctx, id)
+		if err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		if !reflect.DeepEqual(copied, expected) {
+			t.Error("the copied events should be correct:", copied)
+		}
+	}
+	if events, _, _ := target.Load(ctx, other); len(events) != 0 {
+		t.Error("the events of other aggregate types should not be copied:", events)
+	}
+
+	t.Log("copy again")
+	if n, err = source.CopyTo(ctx, target, mocks.AggregateType); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if n != 0 {
+		t.Error("there should be no events copied:", n)
+	}
 }
@@ -0,0 +1,100 @@
+// Copyright (c) 2015 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// EventCodec marshals and unmarshals the data of events of a type, for data
+// that can not be stored with the default BSON encoding.
+type EventCodec struct {
+	Marshal   func(interface{}) (bson.Raw, error)
+	Unmarshal func(bson.Raw, interface{}) error
+}
+
+var eventCodecs = make(map[eh.EventType]EventCodec)
+var eventCodecsMu sync.RWMutex
+
+// RegisterEventCodec registers the functions to marshal and unmarshal the data
+// of events of a type, used instead of the default BSON encoding when saving
+// and loading the events. Unmarshal decodes into the data created by the
+// factory registered with eh.RegisterEventData.
+//
+// An example would be:
+//
+//	RegisterEventCodec(PriceChangedEvent, marshalPrice, unmarshalPrice)
+func RegisterEventCodec(eventType eh.EventType, marshal func(interface{}) (bson.Raw, error), unmarshal func(bson.Raw, interface{}) error) {
+	if eventType == eh.EventType("") {
+		panic("eventhorizon: attempt to register empty event type")
+	}
+	if marshal == nil || unmarshal == nil {
+		panic(fmt.Sprintf("eventhorizon: missing codec functions for %q", eventType))
+	}
+
+	eventCodecsMu.Lock()
+	defer eventCodecsMu.Unlock()
+	if _, ok := eventCodecs[eventType]; ok {
+		panic(fmt.Sprintf("eventhorizon: registering duplicate codecs for %q", eventType))
+	}
+	eventCodecs[eventType] = EventCodec{Marshal: marshal, Unmarshal: unmarshal}
+}
+
+// UnregisterEventCodec removes the codec of an event type, to use the default
+// BSON encoding again.
+func UnregisterEventCodec(eventType eh.EventType) {
+	if eventType == eh.EventType("") {
+		panic("eventhorizon: attempt to unregister empty event type")
+	}
+
+	eventCodecsMu.Lock()
+	defer eventCodecsMu.Unlock()
+	if _, ok := eventCodecs[eventType]; !ok {
+		panic(fmt.Sprintf("eventhorizon: unregister of non-registered codec %q", eventType))
+	}
+	delete(eventCodecs, eventType)
+}
+
+// marshalEventData marshals the data of an event with the codec of its type,
+// or as BSON if there is none.
+func marshalEventData(eventType eh.EventType, data interface{}) (bson.Raw, error) {
+	eventCodecsMu.RLock()
+	codec, ok := eventCodecs[eventType]
+	eventCodecsMu.RUnlock()
+	if ok {
+		return codec.Marshal(data)
+	}
+
+	raw, err := bson.Marshal(data)
+	return bson.Raw(raw), err
+}
+
+// unmarshalEventData unmarshals the data of an event with the codec of its
+// type, or as BSON if there is none.
+func unmarshalEventData(eventType eh.EventType, raw bson.Raw, data interface{}) error {
+	eventCodecsMu.RLock()
+	codec, ok := eventCodecs[eventType]
+	eventCodecsMu.RUnlock()
+	if ok {
+		return codec.Unmarshal(raw, data)
+	}
+
+	return bson.Unmarshal(raw, data)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2015 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongoOptions "go.mongodb.org/mongo-driver/mongo/options"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/mocks"
+)
+
+func TestEventCodec(t *testing.T) {
+	eh.RegisterEventData(testCodecEventType, func() eh.EventData {
+		return &testCodecEventData{}
+	})
+	defer eh.UnregisterEventData(testCodecEventType)
+
+	// The client connects lazily, no server is needed to encode events.
+	client, err := mongo.Connect(context.Background(),
+		mongoOptions.Client().SetHosts([]string{testOptions().DBHost}))
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	store, err := NewEventStoreWithClient(client)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	ctx := eh.NewContextWithNamespaceAndType(context.Background(), "ns", "agg")
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	roundTrip := func() (*dbEvent, eh.Event, error) {
+		e, err := newDBEvent(ctx, eh.NewEventForAggregate(testCodecEventType,
+			&testCodecEventData{price: "9.99"}, timestamp, mocks.AggregateType, uuid.New().String(), 1))
+		if err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		raw, err := bson.Marshal(e)
+		if err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		event, err := store.decodeEvent(ctx, raw)
+		return e, event, err
+	}
+
+	t.Log("the unexported field is lost without a codec")
+	_, event, err := roundTrip()
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if data := event.Data().(*testCodecEventData); data.price != "" {
+		t.Error("the price should not be stored:", data)
+	}
+
+	t.Log("the codec stores the unexported field")
+	RegisterEventCodec(testCodecEventType, marshalTestCodecEventData, unmarshalTestCodecEventData)
+	defer UnregisterEventCodec(testCodecEventType)
+	e, event, err := roundTrip()
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if price, ok := e.RawData.Lookup("price").StringValueOK(); !ok || price != "9.99" {
+		t.Error("the price should be encoded by the codec:", e.RawData)
+	}
+	if data := event.Data().(*testCodecEventData); data.price != "9.99" {
+		t.Error("the price should be decoded by the codec:", data)
+	}
+
+	t.Log("codec errors")
+	UnregisterEventCodec(testCodecEventType)
+	errCodec := errors.New("codec error")
+	RegisterEventCodec(testCodecEventType, marshalTestCodecEventData,
+		func(bson.Raw, interface{}) error { return errCodec })
+	_, _, err = roundTrip()
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != ErrCouldNotUnmarshalEvent || esErr.BaseErr != errCodec {
+		t.Error("there should be a ErrCouldNotUnmarshalEvent error:", err)
+	}
+}
+
+func TestRegisterEventCodecTwice(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || r != "eventhorizon: registering duplicate codecs for \"TestCodecEventTwice\"" {
+			t.Error("there should have been a panic:", r)
+		}
+	}()
+	RegisterEventCodec("TestCodecEventTwice", marshalTestCodecEventData, unmarshalTestCodecEventData)
+	RegisterEventCodec("TestCodecEventTwice", marshalTestCodecEventData, unmarshalTestCodecEventData)
+}
+
+const testCodecEventType eh.EventType = "TestCodecEvent"
+
+// testCodecEventData has an unexported field, which is not encoded as BSON.
+type testCodecEventData struct {
+	price string
+}
+
+func marshalTestCodecEventData(data interface{}) (bson.Raw, error) {
+	return bson.Marshal(bson.M{"price": data.(*testCodecEventData).price})
+}
+
+func unmarshalTestCodecEventData(raw bson.Raw, data interface{}) error {
+	data.(*testCodecEventData).price = raw.Lookup("price").StringValue()
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2015 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrInvalidCompression is when the compression option is not supported.
+var ErrInvalidCompression = errors.New("invalid compression")
+
+// Compression is the compression of the data of stored events.
+type Compression string
+
+const (
+	// CompressionNone stores the data as a BSON document, the default.
+	CompressionNone Compression = ""
+	// CompressionGzip stores the data compressed with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd stores the data compressed with Zstandard.
+	CompressionZstd Compression = "zstd"
+)
+
+// valid returns if the compression is supported.
+func (c Compression) valid() bool {
+	switch c {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return true
+	}
+	return false
+}
+
+// compress moves the data of the event record to the compressed data, if the
+// data is at least threshold bytes. The data can not be queried when compressed.
+func compress(e *dbEvent, compression Compression, threshold int) error {
+	if compression == CompressionNone || len(e.RawData) == 0 || len(e.RawData) < threshold {
+		return nil
+	}
+
+	var compressed []byte
+	switch compression {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(e.RawData); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		compressed = buf.Bytes()
+	case CompressionZstd:
+		encoder, _ := zstdCodec()
+		compressed = encoder.EncodeAll(e.RawData, nil)
+	default:
+		return ErrInvalidCompression
+	}
+
+	e.CompressedData = compressed
+	e.Compression = compression
+	e.RawData = nil
+	return nil
+}
+
+// decompress restores the data of a compressed event record. Records stored
+// without compression are kept as is.
+func decompress(e *dbEvent) error {
+	if e.Compression == CompressionNone {
+		return nil
+	}
+
+	var raw []byte
+	switch e.Compression {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(e.CompressedData))
+		if err != nil {
+			return err
+		}
+		if raw, err = ioutil.ReadAll(r); err != nil {
+			return err
+		}
+	case CompressionZstd:
+		_, decoder := zstdCodec()
+		var err error
+		if raw, err = decoder.DecodeAll(e.CompressedData, nil); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown compression %q", e.Compression)
+	}
+
+	e.RawData = bson.Raw(raw)
+	e.CompressedData = nil
+	e.Compression = CompressionNone
+	return nil
+}
+
+var (
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdOnce    sync.Once
+)
+
+// zstdCodec returns the shared Zstandard encoder and decoder, which are safe
+// for concurrent use with EncodeAll and DecodeAll.
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder) {
+	zstdOnce.Do(func() {
+		// Creating them without a reader or writer can not fail.
+		zstdEncoder, _ = zstd.NewWriter(nil)
+		zstdDecoder, _ = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder
+}
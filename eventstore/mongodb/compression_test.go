@@ -0,0 +1,124 @@
+// Copyright (c) 2015 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongoOptions "go.mongodb.org/mongo-driver/mongo/options"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/mocks"
+)
+
+func TestCompression(t *testing.T) {
+	// The client connects lazily, no server is needed to encode events.
+	client, err := mongo.Connect(context.Background(),
+		mongoOptions.Client().SetHosts([]string{testOptions().DBHost}))
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	store, err := NewEventStoreWithClient(client)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	ctx := eh.NewContextWithNamespaceAndType(context.Background(), "ns", "agg")
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	data := &mocks.EventData{Content: strings.Repeat("large ", 100)}
+	roundTrip := func() (*dbEvent, eh.Event) {
+		e, err := store.newDBEvent(ctx, eh.NewEventForAggregate(mocks.EventType,
+			data, timestamp, mocks.AggregateType, uuid.New().String(), 1))
+		if err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		raw, err := bson.Marshal(e)
+		if err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		decoded, err := store.decodeEvent(ctx, raw)
+		if err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		return e, decoded
+	}
+
+	for _, compression := range []Compression{CompressionGzip, CompressionZstd} {
+		t.Log("compress with", compression)
+		store.compression = compression
+		store.compressionThreshold = 0
+		e, decoded := roundTrip()
+		if e.Compression != compression || len(e.RawData) != 0 {
+			t.Error("the data should be compressed:", e.Compression, len(e.RawData))
+		}
+		if len(e.CompressedData) >= len(data.Content) {
+			t.Error("the data should be smaller:", len(e.CompressedData))
+		}
+		if !reflect.DeepEqual(decoded.Data(), data) {
+			t.Error("the data should be decompressed:", decoded.Data())
+		}
+
+		t.Log("do not compress below the threshold")
+		store.compressionThreshold = 10000
+		e, decoded = roundTrip()
+		if e.Compression != CompressionNone || len(e.CompressedData) != 0 {
+			t.Error("the data should not be compressed:", e.Compression)
+		}
+		if !reflect.DeepEqual(decoded.Data(), data) {
+			t.Error("the data should be decoded:", decoded.Data())
+		}
+	}
+
+	t.Log("load uncompressed events with compression enabled")
+	store.compression = CompressionNone
+	e, _ := roundTrip()
+	raw, err := bson.Marshal(e)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	store.compression = CompressionZstd
+	store.compressionThreshold = 0
+	decoded, err := store.decodeEvent(ctx, raw)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if !reflect.DeepEqual(decoded.Data(), data) {
+		t.Error("the data should be decoded:", decoded.Data())
+	}
+
+	t.Log("fail on corrupt data")
+	e.CompressedData, e.Compression, e.RawData = []byte("corrupt"), CompressionGzip, nil
+	if raw, err = bson.Marshal(e); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	_, err = store.decodeEvent(ctx, raw)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != ErrCouldNotUnmarshalEvent {
+		t.Error("there should be a ErrCouldNotUnmarshalEvent error:", err)
+	}
+
+	t.Log("reject unknown compressions")
+	options := testOptions()
+	options.Compression = "lzma"
+	if _, err := NewEventStore(options); err != ErrInvalidCompression {
+		t.Error("there should be a ErrInvalidCompression error:", err)
+	}
+}
@@ -0,0 +1,572 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package postgres is an event store for PostgreSQL, using database/sql. The
+// table must have the following columns:
+//
+//	CREATE TABLE events (
+//	    namespace      TEXT NOT NULL,
+//	    aggregate_id   TEXT NOT NULL,
+//	    version        INTEGER NOT NULL,
+//	    id             TEXT NOT NULL,
+//	    event_type     TEXT NOT NULL,
+//	    aggregate_type TEXT NOT NULL,
+//	    data           JSONB,
+//	    timestamp      TIMESTAMPTZ NOT NULL,
+//	    UNIQUE (namespace, aggregate_id, version)
+//	);
+//
+// The namespace of the context is stored in the namespace column of every
+// event, all namespaces share the same table instead of a database or schema
+// per namespace like the MongoDB store. Clear deletes the rows of a namespace.
+// The unique version of every aggregate is the optimistic concurrency check, a
+// concurrent save of the same version fails with ErrIncorrectEventVersion. The
+// data is stored as JSON and decoded into the event data registered for the
+// event type.
+//
+// The queries only use SQL that SQLite and PostgreSQL have in common, with $1
+// style placeholders. The tests run against an in-memory SQLite database, as
+// there is no PostgreSQL driver among the dependencies, and do not cover the
+// JSONB and TIMESTAMPTZ column types. Register a driver, like
+// github.com/lib/pq, in the app to use the store with PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// ErrNoDB is when no database is set.
+var ErrNoDB = errors.New("no database")
+
+// ErrInvalidOptions is when the table name can not be used.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// ErrCouldNotClearDB is when the database could not be cleared.
+var ErrCouldNotClearDB = errors.New("could not clear database")
+
+// ErrCouldNotMarshalEvent is when an event could not be marshaled into JSON.
+var ErrCouldNotMarshalEvent = errors.New("could not marshal event")
+
+// ErrCouldNotUnmarshalEvent is when an event could not be unmarshaled into a concrete type.
+var ErrCouldNotUnmarshalEvent = errors.New("could not unmarshal event")
+
+// ErrCouldNotLoadAggregate is when an aggregate could not be loaded.
+var ErrCouldNotLoadAggregate = errors.New("could not load aggregate")
+
+// ErrCouldNotSaveAggregate is when an aggregate could not be saved.
+var ErrCouldNotSaveAggregate = errors.New("could not save aggregate")
+
+// EventStore implements an EventStore for PostgreSQL.
+type EventStore struct {
+	db    *sql.DB
+	table string
+}
+
+// Options are the options for the event store.
+type Options struct {
+	// Table is the name of the table, "events" by default. It can include the
+	// schema, like "eventstore.events".
+	Table string
+}
+
+// identifier is the allowed format of table names.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// NewEventStore creates a new EventStore using a database.
+func NewEventStore(db *sql.DB, options Options) (*EventStore, error) {
+	if db == nil {
+		return nil, ErrNoDB
+	}
+	if options.Table == "" {
+		options.Table = "events"
+	}
+	if !identifier.MatchString(options.Table) {
+		return nil, ErrInvalidOptions
+	}
+
+	s := &EventStore{
+		db:    db,
+		table: options.Table,
+	}
+
+	return s, nil
+}
+
+// Save implements the Save method of the eventhorizon.EventStore interface.
+func (s *EventStore) Save(ctx context.Context, events []eh.Event, originalVersion int) error {
+	if len(events) == 0 {
+		return eh.EventStoreError{
+			Err:           eh.ErrNoEventsToAppend,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	// Build all event records, with incrementing versions starting from the
+	// original aggregate version.
+	dbEvents := make([]dbEvent, len(events))
+	aggregateID := events[0].AggregateID()
+	version := originalVersion
+	for i, event := range events {
+		// Only accept events belonging to the same aggregate.
+		if event.AggregateID() != aggregateID {
+			return eh.EventStoreError{
+				Err:           eh.ErrInvalidEvent,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+
+		// Only accept events that apply to the correct aggregate version.
+		if event.Version() != version+1 {
+			return eh.EventStoreError{
+				Err:           eh.ErrIncorrectEventVersion,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+
+		e, err := newDBEvent(event)
+		if err != nil {
+			return eh.EventStoreError{
+				BaseErr:       err,
+				Err:           ErrCouldNotMarshalEvent,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+				EventType:     event.EventType(),
+			}
+		}
+		dbEvents[i] = e
+		version++
+	}
+
+	if err := s.save(ctx, aggregateID, dbEvents, originalVersion); err != nil {
+		return s.saveError(ctx, aggregateID, originalVersion, err)
+	}
+	return nil
+}
+
+// save inserts the events in a transaction, if the aggregate is still at the
+// original version.
+func (s *EventStore) save(ctx context.Context, aggregateID string, dbEvents []dbEvent, originalVersion int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	version, err := s.version(ctx, tx, aggregateID)
+	if err != nil {
+		return err
+	}
+	if version != originalVersion {
+		return errVersionMismatch{version: version}
+	}
+
+	insert, err := tx.PrepareContext(ctx, s.query(
+		"INSERT INTO %[1]s (namespace, aggregate_id, version, id, event_type, aggregate_type, data, timestamp) "+
+			"VALUES (%[2]s, %[3]s, %[4]s, %[5]s, %[6]s, %[7]s, %[8]s, %[9]s)", 8))
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+	for _, e := range dbEvents {
+		if _, err := insert.ExecContext(ctx,
+			eh.NamespaceFromContext(ctx), e.AggregateID, e.Version, e.ID,
+			string(e.EventType), string(e.AggregateType), e.data(), e.Timestamp,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// saveError returns the error of a failed save. Inserting a version that is
+// already stored, by a concurrent save, fails on the unique constraint with a
+// driver specific error. Those are told apart from other failures by reading
+// the version of the aggregate again.
+func (s *EventStore) saveError(ctx context.Context, aggregateID string, originalVersion int, err error) error {
+	if _, ok := err.(errVersionMismatch); !ok {
+		if version, verr := s.version(ctx, s.db, aggregateID); verr != nil || version == originalVersion {
+			return eh.EventStoreError{
+				BaseErr:       err,
+				Err:           ErrCouldNotSaveAggregate,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+	}
+	return eh.EventStoreError{
+		BaseErr:       err,
+		Err:           eh.ErrIncorrectEventVersion,
+		Namespace:     eh.NamespaceFromContext(ctx),
+		AggregateType: eh.AggregateTypeFromContext(ctx),
+	}
+}
+
+// errVersionMismatch is when the stored version is not the original version.
+type errVersionMismatch struct {
+	version int
+}
+
+// Error implements the Error method of the error interface.
+func (e errVersionMismatch) Error() string {
+	return fmt.Sprintf("aggregate is at version %d", e.version)
+}
+
+// queryRower is a *sql.DB or *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// version returns the stored version of an aggregate, 0 if it has no events.
+func (s *EventStore) version(ctx context.Context, db queryRower, aggregateID string) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, s.query(
+		"SELECT COALESCE(MAX(version), 0) FROM %[1]s WHERE namespace = %[2]s AND aggregate_id = %[3]s", 2),
+		eh.NamespaceFromContext(ctx), aggregateID,
+	).Scan(&version)
+	return version, err
+}
+
+// Load implements the Load method of the eventhorizon.EventStore interface.
+// The versions, limit and order of the loaded events are set by the
+// eh.LoadOptions of the context.
+func (s *EventStore) Load(ctx context.Context, id string) ([]eh.Event, context.Context, error) {
+	opts, _ := eh.LoadOptionsFromContext(ctx)
+	query := "SELECT id, event_type, aggregate_type, data, timestamp, version FROM %[1]s " +
+		"WHERE namespace = %[2]s AND aggregate_id = %[3]s"
+	args := []interface{}{eh.NamespaceFromContext(ctx), id}
+	if opts.MinVersion > 0 {
+		args = append(args, opts.MinVersion)
+		query += fmt.Sprintf(" AND version >= %%[%d]s", len(args)+1)
+	}
+	if opts.MaxVersion > 0 {
+		args = append(args, opts.MaxVersion)
+		query += fmt.Sprintf(" AND version <= %%[%d]s", len(args)+1)
+	}
+	if opts.Sort == eh.LoadSortDescending {
+		query += " ORDER BY version DESC"
+	} else {
+		query += " ORDER BY version"
+	}
+	if opts.Limit > 0 {
+		args = append(args, opts.Limit)
+		query += fmt.Sprintf(" LIMIT %%[%d]s", len(args)+1)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.query(query, len(args)), args...)
+	if err != nil {
+		return nil, ctx, eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotLoadAggregate,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	defer rows.Close()
+
+	events := []eh.Event{}
+	for rows.Next() {
+		e := dbEvent{AggregateID: id}
+		var eventType, aggregateType string
+		if err := rows.Scan(&e.ID, &eventType, &aggregateType,
+			&e.RawData, &e.Timestamp, &e.Version); err != nil {
+			return nil, ctx, eh.EventStoreError{
+				BaseErr:       err,
+				Err:           ErrCouldNotLoadAggregate,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+		e.EventType = eh.EventType(eventType)
+		e.AggregateType = eh.AggregateType(aggregateType)
+
+		event, err := decodeEvent(e)
+		if err != nil {
+			return nil, ctx, eh.EventStoreError{
+				BaseErr:       err,
+				Err:           ErrCouldNotUnmarshalEvent,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+				EventType:     e.EventType,
+			}
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ctx, eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotLoadAggregate,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	// Tell an aggregate without events in the loaded versions apart from a
+	// missing one.
+	if len(events) == 0 {
+		version, err := s.version(ctx, s.db, id)
+		if err != nil {
+			return nil, ctx, eh.EventStoreError{
+				BaseErr:       err,
+				Err:           ErrCouldNotLoadAggregate,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		} else if version == 0 {
+			return events, ctx, eh.EventStoreError{
+				Err:           eh.ErrAggregateNotFound,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+	}
+
+	return events, ctx, nil
+}
+
+// Replace implements the Replace method of the eventhorizon.EventStoreMaintainer interface.
+func (s *EventStore) Replace(ctx context.Context, event eh.Event) error {
+	version, err := s.version(ctx, s.db, event.AggregateID())
+	if err != nil {
+		return eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotLoadAggregate,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	} else if version == 0 {
+		return eh.ErrAggregateNotFound
+	}
+
+	e, err := newDBEvent(event)
+	if err != nil {
+		return eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotMarshalEvent,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+			EventType:     event.EventType(),
+		}
+	}
+
+	res, err := s.db.ExecContext(ctx, s.query(
+		"UPDATE %[1]s SET id = %[2]s, event_type = %[3]s, data = %[4]s, timestamp = %[5]s "+
+			"WHERE namespace = %[6]s AND aggregate_id = %[7]s AND version = %[8]s", 7),
+		e.ID, string(e.EventType), e.data(), e.Timestamp,
+		eh.NamespaceFromContext(ctx), e.AggregateID, e.Version,
+	)
+	if err != nil {
+		return eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotSaveAggregate,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return eh.ErrInvalidEvent
+	}
+
+	return nil
+}
+
+// RenameEvent implements the RenameEvent method of the eventhorizon.EventStoreMaintainer interface.
+func (s *EventStore) RenameEvent(ctx context.Context, from, to eh.EventType) (int, error) {
+	res, err := s.db.ExecContext(ctx, s.query(
+		"UPDATE %[1]s SET event_type = %[2]s WHERE namespace = %[3]s AND event_type = %[4]s", 3),
+		string(to), eh.NamespaceFromContext(ctx), string(from),
+	)
+	if err != nil {
+		return 0, eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotSaveAggregate,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotSaveAggregate,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	return int(n), nil
+}
+
+// Clear removes all events in the namespace of the context.
+func (s *EventStore) Clear(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, s.query(
+		"DELETE FROM %[1]s WHERE namespace = %[2]s", 1),
+		eh.NamespaceFromContext(ctx),
+	); err != nil {
+		return eh.EventStoreError{
+			BaseErr:       err,
+			Err:           ErrCouldNotClearDB,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return nil
+}
+
+// Close closes the database.
+func (s *EventStore) Close() error {
+	return s.db.Close()
+}
+
+// query formats a query with the table as %[1]s and n numbered placeholders as
+// %[2]s and on.
+func (s *EventStore) query(format string, n int) string {
+	args := []interface{}{s.table}
+	for i := 1; i <= n; i++ {
+		args = append(args, fmt.Sprintf("$%d", i))
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// dbEvent is the internal event record for the PostgreSQL event store.
+type dbEvent struct {
+	ID            string
+	EventType     eh.EventType
+	RawData       []byte
+	Timestamp     time.Time
+	AggregateType eh.AggregateType
+	AggregateID   string
+	Version       int
+
+	decoded eh.EventData
+}
+
+// newDBEvent returns a new dbEvent for an event, with the data as JSON.
+func newDBEvent(event eh.Event) (dbEvent, error) {
+	e := dbEvent{
+		ID:            event.ID(),
+		EventType:     event.EventType(),
+		Timestamp:     event.Timestamp().UTC(),
+		AggregateType: event.AggregateType(),
+		AggregateID:   event.AggregateID(),
+		Version:       event.Version(),
+	}
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+
+	// Marshal event data if there is any.
+	if event.Data() != nil {
+		raw, err := json.Marshal(event.Data())
+		if err != nil {
+			return dbEvent{}, err
+		}
+		e.RawData = raw
+	}
+
+	return e, nil
+}
+
+// data returns the JSON data as a query argument, nil for events without data.
+func (e dbEvent) data() interface{} {
+	if e.RawData == nil {
+		return nil
+	}
+	return string(e.RawData)
+}
+
+// decodeEvent decodes the JSON data of a record into the data registered for
+// the event type.
+func decodeEvent(e dbEvent) (eh.Event, error) {
+	// Events without data has nothing to decode.
+	if len(e.RawData) == 0 {
+		return event{dbEvent: e}, nil
+	}
+
+	data, err := eh.CreateEventData(e.EventType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the defaults first, fields missing in the stored event keep them.
+	if d, ok := data.(eh.DefaultsProvider); ok {
+		d.ApplyDefaults()
+	}
+	if err := json.Unmarshal(e.RawData, data); err != nil {
+		return nil, err
+	}
+	e.decoded = data
+	e.RawData = nil
+
+	return event{dbEvent: e}, nil
+}
+
+// event is the private implementation of the eventhorizon.Event interface
+// for a PostgreSQL event store.
+type event struct {
+	dbEvent
+}
+
+// ID implements the ID method of the eventhorizon.Event interface.
+func (e event) ID() string {
+	return e.dbEvent.ID
+}
+
+// AggregateID implements the AggregateID method of the eventhorizon.Event interface.
+func (e event) AggregateID() string {
+	return e.dbEvent.AggregateID
+}
+
+// AggregateType implements the AggregateType method of the eventhorizon.Event interface.
+func (e event) AggregateType() eh.AggregateType {
+	return e.dbEvent.AggregateType
+}
+
+// EventType implements the EventType method of the eventhorizon.Event interface.
+func (e event) EventType() eh.EventType {
+	return e.dbEvent.EventType
+}
+
+// Data implements the Data method of the eventhorizon.Event interface.
+func (e event) Data() eh.EventData {
+	return e.dbEvent.decoded
+}
+
+// Version implements the Version method of the eventhorizon.Event interface.
+func (e event) Version() int {
+	return e.dbEvent.Version
+}
+
+// Timestamp implements the Timestamp method of the eventhorizon.Event interface.
+func (e event) Timestamp() time.Time {
+	return e.dbEvent.Timestamp
+}
+
+// String implements the String method of the eventhorizon.Event interface.
+func (e event) String() string {
+	return fmt.Sprintf("%s@%d", e.dbEvent.EventType, e.dbEvent.Version)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/eventstore"
+	"github.com/firawe/eventhorizon/mocks"
+)
+
+func TestEventStore(t *testing.T) {
+	store := newTestEventStore(t, Options{})
+	defer store.Close()
+
+	t.Log("event store with default namespace")
+	eventstore.AcceptanceTest(t, context.Background(), store)
+
+	t.Log("event store with other namespace")
+	ctx := eh.NewContextWithNamespace(context.Background(), "ns")
+	eventstore.AcceptanceTest(t, ctx, store)
+
+	t.Log("event store maintainer")
+	eventstore.MaintainerAcceptanceTest(t, context.Background(), store)
+
+	t.Log("clear a namespace")
+	if err := store.Clear(ctx); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	id := "c1138e5f-f6fb-4dd0-8e79-255c6c8d3756"
+	_, _, err := store.Load(ctx, id)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrAggregateNotFound {
+		t.Error("there should be a ErrAggregateNotFound error:", err)
+	}
+	if events, _, err := store.Load(context.Background(), id); err != nil || len(events) == 0 {
+		t.Error("the other namespace should be kept:", events, err)
+	}
+}
+
+func TestNewEventStore(t *testing.T) {
+	if _, err := NewEventStore(nil, Options{}); err != ErrNoDB {
+		t.Error("there should be a ErrNoDB error:", err)
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	defer db.Close()
+	if _, err := NewEventStore(db, Options{Table: "events; DROP TABLE events"}); err != ErrInvalidOptions {
+		t.Error("there should be a ErrInvalidOptions error:", err)
+	}
+
+	store, err := NewEventStore(db, Options{Table: "eventstore.events"})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if q := store.query("DELETE FROM %[1]s WHERE namespace = %[2]s AND aggregate_id = %[3]s", 2); q != "DELETE FROM eventstore.events WHERE namespace = $1 AND aggregate_id = $2" {
+		t.Error("the query should be correct:", q)
+	}
+}
+
+func TestSaveConflict(t *testing.T) {
+	store := newTestEventStore(t, Options{})
+	defer store.Close()
+	ctx := context.Background()
+
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event1 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+	if err := store.Save(ctx, []eh.Event{event1}, 0); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+
+	t.Log("save over an existing aggregate")
+	other := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "other"},
+		timestamp, mocks.AggregateType, id, 1)
+	err := store.Save(ctx, []eh.Event{other}, 0)
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrIncorrectEventVersion {
+		t.Error("there should be a ErrIncorrectEventVersion error:", err)
+	}
+
+	t.Log("insert a stored version")
+	// Skip the version check to hit the unique constraint, like a concurrent
+	// save would.
+	e, err := newDBEvent(other)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	err = store.saveError(ctx, id, 0, store.save(ctx, id, []dbEvent{e}, 1))
+	if esErr, ok := err.(eh.EventStoreError); !ok || esErr.Err != eh.ErrIncorrectEventVersion {
+		t.Error("there should be a ErrIncorrectEventVersion error:", err)
+	}
+	events, _, err := store.Load(ctx, id)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if len(events) != 1 {
+		t.Fatal("there should be one event:", events)
+	}
+	if err := mocks.CompareEvents(events[0], event1); err != nil {
+		t.Error("the stored event should be kept:", err)
+	}
+	if events[0].ID() == "" || !events[0].Timestamp().Equal(timestamp) {
+		t.Error("the event should be loaded:", events[0].ID(), events[0].Timestamp())
+	}
+}
+
+// newTestEventStore creates an event store in an in-memory SQLite DB, which
+// supports the numbered placeholders of PostgreSQL.
+func newTestEventStore(t *testing.T, options Options) *EventStore {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	// Every connection has its own in-memory DB.
+	db.SetMaxOpenConns(1)
+
+	store, err := NewEventStore(db, options)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	// SQLite only scans TIMESTAMP columns as time.Time, not TIMESTAMPTZ.
+	if _, err := db.Exec(`CREATE TABLE ` + store.table + ` (
+		namespace      TEXT NOT NULL,
+		aggregate_id   TEXT NOT NULL,
+		version        INTEGER NOT NULL,
+		id             TEXT NOT NULL,
+		event_type     TEXT NOT NULL,
+		aggregate_type TEXT NOT NULL,
+		data           JSONB,
+		timestamp      TIMESTAMP NOT NULL,
+		UNIQUE (namespace, aggregate_id, version)
+	)`); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	return store
+}
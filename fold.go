@@ -0,0 +1,33 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventhorizon
+
+// ReduceFunc reduces an event onto a state, returning the new state.
+type ReduceFunc func(state interface{}, event Event) interface{}
+
+// Fold folds a sequence of events into a state by calling reduce for every
+// event in order, starting with the initial state.
+//
+// An example would be:
+//     count := Fold(events, 0, func(s interface{}, e Event) interface{} {
+//         return s.(int) + 1
+//     }).(int)
+func Fold(events []Event, initial interface{}, reduce ReduceFunc) interface{} {
+	state := initial
+	for _, event := range events {
+		state = reduce(state, event)
+	}
+	return state
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventhorizon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFold(t *testing.T) {
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	events := []Event{
+		NewEventForAggregate("added", 3, timestamp, "test", "id", 1),
+		NewEventForAggregate("added", 4, timestamp, "test", "id", 2),
+		NewEventForAggregate("removed", 2, timestamp, "test", "id", 3),
+	}
+
+	type state struct {
+		Sum     int
+		Changes []EventType
+	}
+	result := Fold(events, state{}, func(s interface{}, e Event) interface{} {
+		st := s.(state)
+		switch e.EventType() {
+		case "added":
+			st.Sum += e.Data().(int)
+		case "removed":
+			st.Sum -= e.Data().(int)
+		}
+		st.Changes = append(st.Changes, e.EventType())
+		return st
+	})
+
+	expected := state{
+		Sum:     5,
+		Changes: []EventType{"added", "added", "removed"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Error("the state should be correct:", result)
+	}
+
+	if result := Fold(nil, 42, nil); result != 42 {
+		t.Error("the initial state should be returned for no events:", result)
+	}
+}
@@ -6,25 +6,25 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b // indirect
 	github.com/golang/protobuf v1.3.2 // indirect
-	github.com/google/go-cmp v0.2.0
+	github.com/google/go-cmp v0.5.2
 	github.com/google/uuid v1.1.0
 	github.com/googleapis/gax-go v2.0.0+incompatible // indirect
 	github.com/gorhill/cronexpr v0.0.0-20180427100037-88b0669f7d75
 	github.com/gorilla/websocket v1.4.0
 	github.com/jpillora/backoff v0.0.0-20170918002102-8eab2debe79d
+	github.com/klauspost/compress v1.9.5
 	github.com/kr/pretty v0.1.0
 	github.com/labstack/gommon v0.3.0
+	github.com/mattn/go-sqlite3 v1.14.6
+	go.mongodb.org/mongo-driver v1.5.4
 	go.opencensus.io v0.15.0 // indirect
-	golang.org/x/net v0.0.0-20190613194153-d28f0bde5980 // indirect
 	golang.org/x/oauth2 v0.0.0-20180821212333-d2e6202438be // indirect
-	golang.org/x/sync v0.0.0-20181221193216-37e7f081c4d4 // indirect
 	google.golang.org/api v0.0.0-20180904000447-0ad5a633fea1
 	google.golang.org/appengine v1.1.0 // indirect
 	google.golang.org/genproto v0.0.0-20180831171423-11092d34479b // indirect
 	google.golang.org/grpc v1.14.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22
-	gopkg.in/yaml.v2 v2.2.5 // indirect
 )
 
 go 1.13
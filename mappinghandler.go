@@ -0,0 +1,71 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventhorizon
+
+import (
+	"context"
+	"fmt"
+)
+
+// MappingFunc maps an event onto an entity, which is nil if there is no entity
+// yet. It returns the entity to save, or true to remove the entity.
+type MappingFunc func(Event, Entity) (Entity, bool)
+
+// MappingHandler is an event handler that writes entities to a repo using a
+// mapping func. It is useful for simple 1:1 read models that don't need a full
+// projector.
+type MappingHandler struct {
+	repo ReadWriteRepo
+	fn   MappingFunc
+}
+
+var _ = EventHandler(&MappingHandler{})
+
+// NewMappingHandler creates a new MappingHandler.
+func NewMappingHandler(repo ReadWriteRepo, fn func(Event, Entity) (Entity, bool)) *MappingHandler {
+	return &MappingHandler{
+		repo: repo,
+		fn:   fn,
+	}
+}
+
+// HandlerType implements the HandlerType method of the EventHandler interface.
+func (h *MappingHandler) HandlerType() EventHandlerType {
+	// Using the memory address as handler type, i.e "mapping-handler-0x11351a0".
+	return EventHandlerType(fmt.Sprintf("mapping-handler-%p", h.fn))
+}
+
+// HandleEvent implements the HandleEvent method of the EventHandler interface.
+// It loads the entity for the event, maps it and then saves or removes it.
+func (h *MappingHandler) HandleEvent(ctx context.Context, event Event) error {
+	entity, err := h.repo.Find(ctx, event.AggregateID())
+	if rrErr, ok := err.(RepoError); ok && rrErr.Err == ErrEntityNotFound {
+		entity = nil
+	} else if err != nil {
+		return err
+	}
+
+	newEntity, remove := h.fn(event, entity)
+	if remove {
+		if entity == nil {
+			return nil
+		}
+		return h.repo.Remove(ctx, event.AggregateID())
+	}
+	if newEntity == nil {
+		return nil
+	}
+	return h.repo.Save(ctx, newEntity)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2014 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventhorizon
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMappingHandler(t *testing.T) {
+	repo := &mappingTestRepo{entities: map[string]Entity{}}
+	h := NewMappingHandler(repo, func(e Event, entity Entity) (Entity, bool) {
+		switch e.EventType() {
+		case "created":
+			return &mappingTestEntity{ID: e.AggregateID(), Content: "created"}, false
+		case "updated":
+			m := entity.(*mappingTestEntity)
+			return &mappingTestEntity{ID: m.ID, Content: "updated"}, false
+		case "deleted":
+			return nil, true
+		}
+		return entity, false
+	})
+
+	ctx := context.Background()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	t.Log("create mapping")
+	created := NewEventForAggregate("created", nil, timestamp, "test", "id", 1)
+	if err := h.HandleEvent(ctx, created); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	expected := &mappingTestEntity{ID: "id", Content: "created"}
+	if !reflect.DeepEqual(repo.entities["id"], expected) {
+		t.Error("the entity should be correct:", repo.entities["id"])
+	}
+
+	t.Log("update mapping")
+	updated := NewEventForAggregate("updated", nil, timestamp, "test", "id", 2)
+	if err := h.HandleEvent(ctx, updated); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	expected = &mappingTestEntity{ID: "id", Content: "updated"}
+	if !reflect.DeepEqual(repo.entities["id"], expected) {
+		t.Error("the entity should be correct:", repo.entities["id"])
+	}
+
+	t.Log("delete mapping")
+	deleted := NewEventForAggregate("deleted", nil, timestamp, "test", "id", 3)
+	if err := h.HandleEvent(ctx, deleted); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if _, ok := repo.entities["id"]; ok {
+		t.Error("the entity should be removed")
+	}
+}
+
+type mappingTestEntity struct {
+	ID      string
+	Content string
+}
+
+func (e *mappingTestEntity) EntityID() string {
+	return e.ID
+}
+
+type mappingTestRepo struct {
+	entities map[string]Entity
+}
+
+func (r *mappingTestRepo) Parent() ReadRepo {
+	return nil
+}
+
+func (r *mappingTestRepo) Find(ctx context.Context, id string) (Entity, error) {
+	if e, ok := r.entities[id]; ok {
+		return e, nil
+	}
+	return nil, RepoError{Err: ErrEntityNotFound}
+}
+
+func (r *mappingTestRepo) FindAll(ctx context.Context) ([]Entity, error) {
+	all := []Entity{}
+	for _, e := range r.entities {
+		all = append(all, e)
+	}
+	return all, nil
+}
+
+func (r *mappingTestRepo) Save(ctx context.Context, entity Entity) error {
+	r.entities[entity.EntityID()] = entity
+	return nil
+}
+
+func (r *mappingTestRepo) Remove(ctx context.Context, id string) error {
+	if _, ok := r.entities[id]; !ok {
+		return RepoError{Err: ErrEntityNotFound}
+	}
+	delete(r.entities, id)
+	return nil
+}
@@ -17,18 +17,25 @@ package async
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	eh "github.com/firawe/eventhorizon"
 )
 
 // NewMiddleware returns a new async handling middleware that returns any errors
-// on a error channel.
+// on a error channel. Commands handled with a context from NewContextWithResult
+// report their result on the result channel instead.
 func NewMiddleware() (eh.CommandHandlerMiddleware, chan Error) {
 	errCh := make(chan Error, 20)
 	return eh.CommandHandlerMiddleware(func(h eh.CommandHandler) eh.CommandHandler {
 		return eh.CommandHandlerFunc(func(ctx context.Context, cmd eh.Command) error {
 			go func() {
-				if err := h.HandleCommand(ctx, cmd); err != nil {
+				err := h.HandleCommand(ctx, cmd)
+				if r, ok := ctx.Value(resultKey).(*result); ok {
+					r.resolve(err)
+					return
+				}
+				if err != nil {
 					// Always try to deliver errors.
 					errCh <- Error{err, ctx, cmd}
 				}
@@ -38,6 +45,33 @@ func NewMiddleware() (eh.CommandHandlerMiddleware, chan Error) {
 	}), errCh
 }
 
+type contextKey int
+
+const resultKey contextKey = iota
+
+// NewContextWithResult returns a context for awaiting the result of handling
+// a command asynchronously. The returned channel receives the error from the
+// handler, or nil on success, when the handler has finished and is then
+// closed. The context should only be used for a single command.
+func NewContextWithResult(ctx context.Context) (context.Context, <-chan error) {
+	r := &result{ch: make(chan error, 1)}
+	return context.WithValue(ctx, resultKey, r), r.ch
+}
+
+// result is the pending result of a command.
+type result struct {
+	ch   chan error
+	once sync.Once
+}
+
+// resolve delivers the result, only the first result is delivered.
+func (r *result) resolve(err error) {
+	r.once.Do(func() {
+		r.ch <- err
+		close(r.ch)
+	})
+}
+
 // Error is an error containing the error and the command.
 type Error struct {
 	Err     error
@@ -75,3 +75,55 @@ func TestCommandHandler(t *testing.T) {
 		t.Error("the command shoud not have been handeled:", inner.Commands)
 	}
 }
+
+func TestCommandHandlerResult(t *testing.T) {
+	cmd := mocks.Command{
+		ID:      uuid.New().String(),
+		Content: "content",
+	}
+
+	inner := &mocks.CommandHandler{}
+	m, errCh := NewMiddleware()
+	h := eh.UseCommandHandlerMiddleware(inner, m)
+	ctx, result := NewContextWithResult(context.Background())
+	if err := h.HandleCommand(ctx, cmd); err != nil {
+		t.Error("there should never be an error:", err)
+	}
+	select {
+	case err, ok := <-result:
+		if !ok || err != nil {
+			t.Error("there should be a successful result:", err, ok)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("there should be a result")
+	}
+	if _, ok := <-result; ok {
+		t.Error("the result channel should be closed")
+	}
+	if !reflect.DeepEqual(inner.Commands, []eh.Command{cmd}) {
+		t.Error("the command shoud have been handeled:", inner.Commands)
+	}
+
+	t.Log("error propagation")
+	inner = &mocks.CommandHandler{}
+	handlingErr := errors.New("handling error")
+	inner.Err = handlingErr
+	h = eh.UseCommandHandlerMiddleware(inner, m)
+	ctx, result = NewContextWithResult(context.Background())
+	if err := h.HandleCommand(ctx, cmd); err != nil {
+		t.Error("there should never be an error:", err)
+	}
+	select {
+	case err := <-result:
+		if err != handlingErr {
+			t.Error("the error should be correct:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("there should be a result")
+	}
+	select {
+	case err := <-errCh:
+		t.Error("the error should only be sent as the result:", err)
+	case <-time.After(time.Millisecond):
+	}
+}
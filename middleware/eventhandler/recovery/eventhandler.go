@@ -0,0 +1,64 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// NewMiddleware returns a new middleware that recovers from panics in the
+// handler and returns them as an Error. The panic is then handled like any
+// other error of the handler, for example sent on the error channel of the
+// event bus, instead of crashing the goroutine handling the events.
+func NewMiddleware() eh.EventHandlerMiddleware {
+	return eh.EventHandlerMiddleware(func(h eh.EventHandler) eh.EventHandler {
+		return &eventHandler{h}
+	})
+}
+
+// eventHandler keeps the handler type of the wrapped handler, which is used
+// to register it on event buses.
+type eventHandler struct {
+	eh.EventHandler
+}
+
+// HandleEvent implements the HandleEvent method of the eventhorizon.EventHandler interface.
+func (h *eventHandler) HandleEvent(ctx context.Context, event eh.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Error{Value: r, Stack: debug.Stack(), Event: event}
+		}
+	}()
+
+	return h.EventHandler.HandleEvent(ctx, event)
+}
+
+// Error is a recovered panic containing the panic value, the stack trace of
+// the panic and the event.
+type Error struct {
+	Value interface{}
+	Stack []byte
+	Event eh.Event
+}
+
+// Error implements the Error method of the error interface. It includes the
+// stack trace, which is lost when only the message of the error is kept.
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: panic: %v\n%s", e.Event.String(), e.Value, e.Stack)
+}
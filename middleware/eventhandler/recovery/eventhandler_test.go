@@ -0,0 +1,119 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recovery
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/eventbus/local"
+	"github.com/firawe/eventhorizon/mocks"
+	"github.com/google/uuid"
+)
+
+func TestEventHandler(t *testing.T) {
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+
+	inner := mocks.NewEventHandler("test")
+	h := eh.UseEventHandlerMiddleware(inner, NewMiddleware())
+	if h.HandlerType() != inner.HandlerType() {
+		t.Error("the handler type should be kept:", h.HandlerType())
+	}
+	if err := h.HandleEvent(context.Background(), event); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(inner.Events) != 1 {
+		t.Error("the event should be handled:", inner.Events)
+	}
+
+	t.Log("recover from a panic")
+	h = eh.UseEventHandlerMiddleware(&panicHandler{}, NewMiddleware())
+	err := h.HandleEvent(context.Background(), event)
+	rErr, ok := err.(Error)
+	if !ok {
+		t.Fatal("there should be a recovery error:", err)
+	}
+	if rErr.Value != "handler panic" || rErr.Event != event {
+		t.Error("the error should be correct:", rErr)
+	}
+	if !strings.Contains(string(rErr.Stack), "panicHandler") {
+		t.Error("the stack should be from the panic:", string(rErr.Stack))
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	bus := local.NewEventBus(nil)
+	defer bus.Close()
+	h := &panicHandler{recv: make(chan eh.Event, 10)}
+	bus.AddHandler(eh.MatchAny(), eh.UseEventHandlerMiddleware(h, NewMiddleware()))
+
+	id := uuid.New().String()
+	timestamp := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	event1 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event1"},
+		timestamp, mocks.AggregateType, id, 1)
+	event2 := eh.NewEventForAggregate(mocks.EventType, &mocks.EventData{Content: "event2"},
+		timestamp, mocks.AggregateType, id, 2)
+
+	t.Log("the panic should be sent as an error")
+	if err := bus.PublishEvent(context.Background(), event1); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	select {
+	case err := <-bus.Errors():
+		if !strings.Contains(err.Error(), "panic: handler panic") || err.Event != event1 {
+			t.Error("the error should be from the panic:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("there should be an error")
+	}
+
+	t.Log("the bus should keep handling events")
+	h.recovered = true
+	if err := bus.PublishEvent(context.Background(), event2); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	select {
+	case e := <-h.recv:
+		if e != event2 {
+			t.Error("the event should be correct:", e)
+		}
+	case <-time.After(time.Second):
+		t.Error("the event should be handled after the panic")
+	}
+}
+
+// panicHandler panics on all events until recovered is set.
+type panicHandler struct {
+	recovered bool
+	recv      chan eh.Event
+}
+
+func (h *panicHandler) HandlerType() eh.EventHandlerType {
+	return "panic"
+}
+
+func (h *panicHandler) HandleEvent(ctx context.Context, event eh.Event) error {
+	if !h.recovered {
+		panic("handler panic")
+	}
+	h.recv <- event
+	return nil
+}
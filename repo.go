@@ -70,6 +70,53 @@ type WriteRepo interface {
 	Remove(context.Context, string) error
 }
 
+// ErrUnsupportedQuery is when a query is not supported by the repository.
+var ErrUnsupportedQuery = errors.New("unsupported query")
+
+// QueryRepo is a read repository that can query entities in the storage.
+// It is optional, wrapping repos return ErrUnsupportedQuery if their parent
+// does not implement it.
+type QueryRepo interface {
+	ReadRepo
+
+	// FindWithFilter returns all entities where each field of the filter is
+	// equal to its value, in the same order as FindAll. The fields are named
+	// as in the storage, for example by their JSON or BSON tags.
+	FindWithFilter(context.Context, map[string]interface{}) ([]Entity, error)
+}
+
+// PagedRepo is a read repository that can page through its entities. It is
+// optional in the same way as QueryRepo.
+type PagedRepo interface {
+	ReadRepo
+
+	// FindAllPaged returns at most limit entities, skipping the first offset
+	// entities in the order of FindAll, and the total number of entities.
+	// A limit below 1 returns all entities after the offset.
+	FindAllPaged(ctx context.Context, offset, limit int) ([]Entity, int, error)
+}
+
+// SortedRepo is a read repository that can sort its entities. It is optional
+// in the same way as QueryRepo.
+type SortedRepo interface {
+	ReadRepo
+
+	// FindAllSorted returns all entities sorted by a field, named as in the
+	// storage. The order of entities with equal values depends on the repo.
+	FindAllSorted(ctx context.Context, field string, ascending bool) ([]Entity, error)
+}
+
+// BulkRepo is a write repository that can save many entities at once. It is
+// optional, wrapping repos save the entities one by one if their parent does
+// not implement it.
+type BulkRepo interface {
+	WriteRepo
+
+	// SaveAll saves the entities in the storage, in one batch if the storage
+	// supports it. No entity is saved if any of them has no ID.
+	SaveAll(context.Context, []Entity) error
+}
+
 // ReadWriteRepo is a combined read and write repo, mainly useful for testing.
 type ReadWriteRepo interface {
 	ReadRepo
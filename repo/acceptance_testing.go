@@ -16,6 +16,7 @@ package repo
 
 import (
 	"context"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"testing"
 	"time"
@@ -36,11 +37,10 @@ import (
 //   }
 //
 
+// comparer compares times with millisecond precision, the precision of the
+// time in MongoDB.
 var comparer = cmp.Comparer(func(a, b time.Time) bool {
-	if a.UTC().Unix() == b.UTC().Unix() {
-		return true
-	}
-	return false
+	return a.UTC().Truncate(time.Millisecond).Equal(b.UTC().Truncate(time.Millisecond))
 })
 
 func AcceptanceTest(t *testing.T, ctx context.Context, repo eh.ReadWriteRepo) {
@@ -119,11 +119,11 @@ func AcceptanceTest(t *testing.T, ctx context.Context, repo eh.ReadWriteRepo) {
 		t.Error("not equal expected: ", cmp.Diff(entity, entity1Alt, comparer))
 	}
 
-	// Save with another ID.
+	// Save with another ID, and a time with sub-second precision.
 	entity2 := &mocks.Model{
 		ID:        uuid.New().String(),
 		Content:   "entity2",
-		CreatedAt: time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+		CreatedAt: time.Date(2009, time.November, 10, 23, 0, 0, 123000000, time.UTC),
 	}
 	if err = repo.Save(ctx, entity2); err != nil {
 		t.Error("there should be no error:", err)
@@ -136,6 +136,9 @@ func AcceptanceTest(t *testing.T, ctx context.Context, repo eh.ReadWriteRepo) {
 	if !cmp.Equal(entity, entity2, comparer) {
 		t.Error("not equal expected: ", cmp.Diff(entity, entity2, comparer))
 	}
+	if m, ok := entity.(*mocks.Model); !ok || !m.CreatedAt.Equal(entity2.CreatedAt) {
+		t.Error("the time should keep its sub-second precision:", entity)
+	}
 	// FindAll with two items, order should be preserved from insert.
 	result, err = repo.FindAll(ctx)
 	if err != nil {
@@ -148,6 +151,89 @@ func AcceptanceTest(t *testing.T, ctx context.Context, repo eh.ReadWriteRepo) {
 		t.Error("not equal expected: ", cmp.Diff(result, []eh.Entity{entity1Alt, entity2}, comparer))
 	}
 
+	// Filter by a field, if supported.
+	if queryRepo, ok := repo.(eh.QueryRepo); ok {
+		result, err = queryRepo.FindWithFilter(ctx, map[string]interface{}{"content": "entity2"})
+		if rrErr, ok := err.(eh.RepoError); ok && rrErr.Err == eh.ErrUnsupportedQuery {
+			// A wrapping repo with a parent without queries.
+		} else {
+			if err != nil {
+				t.Error("there should be no error:", err)
+			}
+			if !cmp.Equal(result, []eh.Entity{entity2}, comparer) {
+				t.Error("not equal expected: ", cmp.Diff(result, []eh.Entity{entity2}, comparer))
+			}
+			result, err = queryRepo.FindWithFilter(ctx, map[string]interface{}{"content": "none"})
+			if err != nil {
+				t.Error("there should be no error:", err)
+			}
+			if len(result) != 0 {
+				t.Error("there should be no items:", len(result))
+			}
+			result, err = queryRepo.FindWithFilter(ctx, map[string]interface{}{})
+			if err != nil {
+				t.Error("there should be no error:", err)
+			}
+			if !cmp.Equal(result, []eh.Entity{entity1Alt, entity2}, comparer) {
+				t.Error("not equal expected: ", cmp.Diff(result, []eh.Entity{entity1Alt, entity2}, comparer))
+			}
+		}
+	}
+
+	// Page through the items in insert order, if supported.
+	if pagedRepo, ok := repo.(eh.PagedRepo); ok {
+		_, _, err = pagedRepo.FindAllPaged(ctx, 0, 1)
+		if rrErr, ok := err.(eh.RepoError); ok && rrErr.Err == eh.ErrUnsupportedQuery {
+			// A wrapping repo with a parent without paging.
+		} else {
+			pages := []struct {
+				offset, limit int
+				expected      []eh.Entity
+			}{
+				{0, 1, []eh.Entity{entity1Alt}},
+				{1, 1, []eh.Entity{entity2}},
+				{2, 1, []eh.Entity{}},
+				{0, 5, []eh.Entity{entity1Alt, entity2}},
+				{1, 0, []eh.Entity{entity2}},
+				{0, 0, []eh.Entity{entity1Alt, entity2}},
+			}
+			for _, page := range pages {
+				result, total, err := pagedRepo.FindAllPaged(ctx, page.offset, page.limit)
+				if err != nil {
+					t.Error("there should be no error:", err)
+				}
+				if total != 2 {
+					t.Error("the total should be two:", page.offset, page.limit, total)
+				}
+				if !cmp.Equal(result, page.expected, comparer) {
+					t.Error("not equal expected: ", page.offset, page.limit, cmp.Diff(result, page.expected, comparer))
+				}
+			}
+		}
+	}
+
+	// Sort by the creation time, if supported.
+	if sortedRepo, ok := repo.(eh.SortedRepo); ok {
+		result, err = sortedRepo.FindAllSorted(ctx, "created_at", true)
+		if rrErr, ok := err.(eh.RepoError); ok && rrErr.Err == eh.ErrUnsupportedQuery {
+			// A wrapping repo with a parent without sorting.
+		} else {
+			if err != nil {
+				t.Error("there should be no error:", err)
+			}
+			if !cmp.Equal(result, []eh.Entity{entity1Alt, entity2}, comparer) {
+				t.Error("not equal expected: ", cmp.Diff(result, []eh.Entity{entity1Alt, entity2}, comparer))
+			}
+			result, err = sortedRepo.FindAllSorted(ctx, "created_at", false)
+			if err != nil {
+				t.Error("there should be no error:", err)
+			}
+			if !cmp.Equal(result, []eh.Entity{entity2, entity1Alt}, comparer) {
+				t.Error("not equal expected: ", cmp.Diff(result, []eh.Entity{entity2, entity1Alt}, comparer))
+			}
+		}
+	}
+
 	// Remove item.
 	if err := repo.Remove(ctx, entity1Alt.ID); err != nil {
 		t.Error("there should be no error:", err)
@@ -165,4 +251,89 @@ func AcceptanceTest(t *testing.T, ctx context.Context, repo eh.ReadWriteRepo) {
 	if rrErr, ok := err.(eh.RepoError); !ok || rrErr.Err != eh.ErrEntityNotFound {
 		t.Error("there should be a ErrEntityNotFound error:", err)
 	}
+
+	// Save in one namespace, which is not visible in another.
+	ctxA := eh.NewContextWithNamespace(ctx, "ns-a")
+	ctxB := eh.NewContextWithNamespace(ctx, "ns-b")
+	entity3 := &mocks.Model{
+		ID:        uuid.New().String(),
+		Content:   "entity3",
+		CreatedAt: time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+	}
+	if err = repo.Save(ctxA, entity3); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	entity, err = repo.Find(ctxB, entity3.ID)
+	if rrErr, ok := err.(eh.RepoError); !ok || rrErr.Err != eh.ErrEntityNotFound {
+		t.Error("there should be a ErrEntityNotFound error:", err)
+	}
+	if entity != nil {
+		t.Error("there should be no entity:", entity)
+	}
+	result, err = repo.FindAll(ctxB)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(result) != 0 {
+		t.Error("there should be no items:", len(result))
+	}
+	entity, err = repo.Find(ctxA, entity3.ID)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if !cmp.Equal(entity, entity3, comparer) {
+		t.Error("not equal expected: ", cmp.Diff(entity, entity3, comparer))
+	}
+	result, err = repo.FindAll(ctxA)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if !cmp.Equal(result, []eh.Entity{entity3}, comparer) {
+		t.Error("not equal expected: ", cmp.Diff(result, []eh.Entity{entity3}, comparer))
+	}
+	if err := repo.Remove(ctxA, entity3.ID); err != nil {
+		t.Error("there should be no error:", err)
+	}
+
+	// Save many items at once, if supported.
+	if bulkRepo, ok := repo.(eh.BulkRepo); ok {
+		ctxBulk := eh.NewContextWithNamespace(ctx, eh.NamespaceFromContext(ctx)+"-bulk")
+		entity4 := &mocks.Model{
+			ID:        uuid.New().String(),
+			Content:   "entity4",
+			CreatedAt: time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+		}
+		err = bulkRepo.SaveAll(ctxBulk, []eh.Entity{entity4, entityMissingID})
+		if rrErr, ok := err.(eh.RepoError); !ok || rrErr.BaseErr != eh.ErrMissingEntityID {
+			t.Error("there should be a ErrMissingEntityID error:", err)
+		}
+		entity, err = repo.Find(ctxBulk, entity4.ID)
+		if rrErr, ok := err.(eh.RepoError); !ok || rrErr.Err != eh.ErrEntityNotFound {
+			t.Error("there should be a ErrEntityNotFound error:", err)
+		}
+
+		entities := make([]eh.Entity, 1000)
+		for i := range entities {
+			entities[i] = &mocks.Model{
+				ID:        uuid.New().String(),
+				Content:   fmt.Sprintf("bulk%d", i),
+				CreatedAt: time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+			}
+		}
+		if err := bulkRepo.SaveAll(ctxBulk, entities); err != nil {
+			t.Error("there should be no error:", err)
+		}
+		result, err = repo.FindAll(ctxBulk)
+		if err != nil {
+			t.Error("there should be no error:", err)
+		}
+		if !cmp.Equal(result, entities, comparer) {
+			t.Error("not equal expected: ", cmp.Diff(result, entities, comparer))
+		}
+		for _, entity := range entities {
+			if err := repo.Remove(ctxBulk, entity.EntityID()); err != nil {
+				t.Error("there should be no error:", err)
+			}
+		}
+	}
 }
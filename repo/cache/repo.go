@@ -98,6 +98,87 @@ func (r *Repo) FindAll(ctx context.Context) ([]eh.Entity, error) {
 	return entities, nil
 }
 
+// FindAllPaged implements the FindAllPaged method of the
+// eventhorizon.PagedRepo interface, if the parent repo implements it.
+func (r *Repo) FindAllPaged(ctx context.Context, offset, limit int) ([]eh.Entity, int, error) {
+	parent, ok := r.ReadWriteRepo.(eh.PagedRepo)
+	if !ok {
+		return nil, 0, eh.RepoError{
+			Err:           eh.ErrUnsupportedQuery,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	entities, total, err := parent.FindAllPaged(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Cache all items in the page.
+	ns := r.namespace(ctx)
+	r.cacheMu.Lock()
+	for _, entity := range entities {
+		r.cache[ns][entity.EntityID()] = entity
+	}
+	r.cacheMu.Unlock()
+
+	return entities, total, nil
+}
+
+// FindAllSorted implements the FindAllSorted method of the
+// eventhorizon.SortedRepo interface, if the parent repo implements it.
+func (r *Repo) FindAllSorted(ctx context.Context, field string, ascending bool) ([]eh.Entity, error) {
+	parent, ok := r.ReadWriteRepo.(eh.SortedRepo)
+	if !ok {
+		return nil, eh.RepoError{
+			Err:           eh.ErrUnsupportedQuery,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	entities, err := parent.FindAllSorted(ctx, field, ascending)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache all items.
+	ns := r.namespace(ctx)
+	r.cacheMu.Lock()
+	for _, entity := range entities {
+		r.cache[ns][entity.EntityID()] = entity
+	}
+	r.cacheMu.Unlock()
+
+	return entities, nil
+}
+
+// FindWithFilter implements the FindWithFilter method of the
+// eventhorizon.QueryRepo interface, if the parent repo implements it.
+func (r *Repo) FindWithFilter(ctx context.Context, filter map[string]interface{}) ([]eh.Entity, error) {
+	parent, ok := r.ReadWriteRepo.(eh.QueryRepo)
+	if !ok {
+		return nil, eh.RepoError{
+			Err:           eh.ErrUnsupportedQuery,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	entities, err := parent.FindWithFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache all found items.
+	ns := r.namespace(ctx)
+	r.cacheMu.Lock()
+	for _, entity := range entities {
+		r.cache[ns][entity.EntityID()] = entity
+	}
+	r.cacheMu.Unlock()
+
+	return entities, nil
+}
+
 // Save implements the Save method of the eventhorizon.WriteRepo interface.
 func (r *Repo) Save(ctx context.Context, entity eh.Entity) error {
 	// Bust the cache on save.
@@ -109,6 +190,40 @@ func (r *Repo) Save(ctx context.Context, entity eh.Entity) error {
 	return r.ReadWriteRepo.Save(ctx, entity)
 }
 
+// SaveAll implements the SaveAll method of the eventhorizon.BulkRepo interface.
+// The entities are saved one by one if the parent repo does not implement it.
+func (r *Repo) SaveAll(ctx context.Context, entities []eh.Entity) error {
+	// Bust the cache on save.
+	ns := r.namespace(ctx)
+	r.cacheMu.Lock()
+	for _, entity := range entities {
+		delete(r.cache[ns], entity.EntityID())
+	}
+	r.cacheMu.Unlock()
+
+	if parent, ok := r.ReadWriteRepo.(eh.BulkRepo); ok {
+		return parent.SaveAll(ctx, entities)
+	}
+
+	// Save one by one, after checking all IDs like a bulk save.
+	for _, entity := range entities {
+		if len(entity.EntityID()) == 0 {
+			return eh.RepoError{
+				Err:           eh.ErrCouldNotSaveEntity,
+				BaseErr:       eh.ErrMissingEntityID,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+	}
+	for _, entity := range entities {
+		if err := r.ReadWriteRepo.Save(ctx, entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Remove implements the Remove method of the eventhorizon.WriteRepo interface.
 func (r *Repo) Remove(ctx context.Context, id string) error {
 	// Bust the cache on remove.
@@ -190,6 +190,38 @@ func extraRepoTests(t *testing.T, ctx context.Context) {
 	if !baseRepo.FindCalled {
 		t.Error("the item should have been read from the store")
 	}
+
+	// Filter, page and sort without support in the parent.
+	r = NewRepo(baseRepo)
+	_, err = r.FindWithFilter(ctx, map[string]interface{}{"content": "simpleModel"})
+	if rrErr, ok := err.(eh.RepoError); !ok || rrErr.Err != eh.ErrUnsupportedQuery {
+		t.Error("there should be a ErrUnsupportedQuery error:", err)
+	}
+	_, _, err = r.FindAllPaged(ctx, 0, 1)
+	if rrErr, ok := err.(eh.RepoError); !ok || rrErr.Err != eh.ErrUnsupportedQuery {
+		t.Error("there should be a ErrUnsupportedQuery error:", err)
+	}
+	_, err = r.FindAllSorted(ctx, "content", true)
+	if rrErr, ok := err.(eh.RepoError); !ok || rrErr.Err != eh.ErrUnsupportedQuery {
+		t.Error("there should be a ErrUnsupportedQuery error:", err)
+	}
+
+	// Save all one by one without support in the parent.
+	baseRepo = &mocks.Repo{}
+	r = NewRepo(baseRepo)
+	err = r.SaveAll(ctx, []eh.Entity{simpleModel, &mocks.SimpleModel{}})
+	if rrErr, ok := err.(eh.RepoError); !ok || rrErr.BaseErr != eh.ErrMissingEntityID {
+		t.Error("there should be a ErrMissingEntityID error:", err)
+	}
+	if baseRepo.SaveCalled {
+		t.Error("no item should be saved")
+	}
+	if err := r.SaveAll(ctx, []eh.Entity{simpleModel}); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if baseRepo.Entity != simpleModel {
+		t.Error("the item should be saved")
+	}
 }
 
 func TestRepository(t *testing.T) {
@@ -15,8 +15,12 @@
 package memory
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"sort"
 	"sync"
+	"time"
 
 	eh "github.com/firawe/eventhorizon"
 )
@@ -65,17 +69,82 @@ func (r *Repo) Find(ctx context.Context, id string) (eh.Entity, error) {
 
 // FindAll implements the FindAll method of the eventhorizon.ReadRepo interface.
 func (r *Repo) FindAll(ctx context.Context) ([]eh.Entity, error) {
+	all, _, err := r.FindAllPaged(ctx, 0, 0)
+	return all, err
+}
+
+// FindAllPaged implements the FindAllPaged method of the
+// eventhorizon.PagedRepo interface.
+func (r *Repo) FindAllPaged(ctx context.Context, offset, limit int) ([]eh.Entity, int, error) {
 	ns := r.namespace(ctx)
 
 	r.dbMu.RLock()
 	defer r.dbMu.RUnlock()
+	ids := r.ids[ns]
+	total := len(ids)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	ids = ids[offset:]
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+
 	all := []eh.Entity{}
-	for _, id := range r.ids[ns] {
+	for _, id := range ids {
 		if m, ok := r.db[ns][id]; ok {
 			all = append(all, m)
 		}
 	}
 
+	return all, total, nil
+}
+
+// FindWithFilter implements the FindWithFilter method of the
+// eventhorizon.QueryRepo interface. The fields are matched by the JSON
+// encoding of the entities.
+func (r *Repo) FindWithFilter(ctx context.Context, filter map[string]interface{}) ([]eh.Entity, error) {
+	all, err := r.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []eh.Entity{}
+	for _, entity := range all {
+		ok, err := matches(entity, filter)
+		if err != nil {
+			return nil, eh.RepoError{
+				Err:       err,
+				Namespace: eh.NamespaceFromContext(ctx),
+			}
+		}
+		if ok {
+			result = append(result, entity)
+		}
+	}
+
+	return result, nil
+}
+
+// FindAllSorted implements the FindAllSorted method of the
+// eventhorizon.SortedRepo interface. The field is read from the JSON encoding
+// of the entities.
+func (r *Repo) FindAllSorted(ctx context.Context, field string, ascending bool) ([]eh.Entity, error) {
+	all, err := r.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sortByField(all, field, ascending); err != nil {
+		return nil, eh.RepoError{
+			Err:       err,
+			Namespace: eh.NamespaceFromContext(ctx),
+		}
+	}
+
 	return all, nil
 }
 
@@ -102,6 +171,33 @@ func (r *Repo) Save(ctx context.Context, entity eh.Entity) error {
 	return nil
 }
 
+// SaveAll implements the SaveAll method of the eventhorizon.BulkRepo interface.
+func (r *Repo) SaveAll(ctx context.Context, entities []eh.Entity) error {
+	ns := r.namespace(ctx)
+
+	for _, entity := range entities {
+		if len(entity.EntityID()) == 0 {
+			return eh.RepoError{
+				Err:       eh.ErrCouldNotSaveEntity,
+				BaseErr:   eh.ErrMissingEntityID,
+				Namespace: eh.NamespaceFromContext(ctx),
+			}
+		}
+	}
+
+	r.dbMu.Lock()
+	defer r.dbMu.Unlock()
+	for _, entity := range entities {
+		id := entity.EntityID()
+		if _, ok := r.db[ns][id]; !ok {
+			r.ids[ns] = append(r.ids[ns], id)
+		}
+		r.db[ns][id] = entity
+	}
+
+	return nil
+}
+
 // Remove implements the Remove method of the eventhorizon.WriteRepo interface.
 func (r *Repo) Remove(ctx context.Context, id string) error {
 	ns := r.namespace(ctx)
@@ -143,6 +239,97 @@ func (r *Repo) namespace(ctx context.Context) namespace {
 	return ns
 }
 
+// matches returns if the JSON fields of the entity are equal to the JSON
+// encoded values of the filter.
+func matches(entity eh.Entity, filter map[string]interface{}) (bool, error) {
+	b, err := json.Marshal(entity)
+	if err != nil {
+		return false, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return false, err
+	}
+
+	for name, value := range filter {
+		field, ok := fields[name]
+		if !ok {
+			return false, nil
+		}
+		v, err := json.Marshal(value)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(field, v) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// sortByField sorts the entities by the JSON value of a field. Missing and
+// null values are sorted first and strings in RFC 3339 format as times.
+func sortByField(entities []eh.Entity, field string, ascending bool) error {
+	type item struct {
+		entity eh.Entity
+		value  interface{}
+	}
+	items := make([]item, len(entities))
+	for i, entity := range entities {
+		b, err := json.Marshal(entity)
+		if err != nil {
+			return err
+		}
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return err
+		}
+		items[i].entity = entity
+		if raw, ok := fields[field]; ok {
+			if err := json.Unmarshal(raw, &items[i].value); err != nil {
+				return err
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if ascending {
+			return less(items[i].value, items[j].value)
+		}
+		return less(items[j].value, items[i].value)
+	})
+	for i := range items {
+		entities[i] = items[i].entity
+	}
+	return nil
+}
+
+// less compares two decoded JSON values of the same type.
+func less(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b != nil
+	case float64:
+		b, ok := b.(float64)
+		return ok && a < b
+	case bool:
+		b, ok := b.(bool)
+		return ok && !a && b
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return false
+		}
+		ta, errA := time.Parse(time.RFC3339Nano, a)
+		tb, errB := time.Parse(time.RFC3339Nano, b)
+		if errA == nil && errB == nil {
+			return ta.Before(tb)
+		}
+		return a < b
+	}
+	return false
+}
+
 // Repository returns a parent ReadRepo if there is one.
 func Repository(repo eh.ReadRepo) *Repo {
 	if repo == nil {
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 
 	eh "github.com/firawe/eventhorizon"
 )
@@ -42,6 +43,9 @@ var ErrModelNotSet = errors.New("model not set")
 // ErrInvalidQuery is when a query was not returned from the callback to FindCustom.
 var ErrInvalidQuery = errors.New("invalid query")
 
+// ErrCouldNotLoadEntity is when entities could not be loaded.
+var ErrCouldNotLoadEntity = errors.New("could not load entity")
+
 // Repo implements an MongoDB repository for entities.
 type Repo struct {
 	session    *mgo.Session
@@ -145,6 +149,58 @@ func (r *Repo) Find(ctx context.Context, id string) (eh.Entity, error) {
 
 // FindAll implements the FindAll method of the eventhorizon.ReadRepo interface.
 func (r *Repo) FindAll(ctx context.Context) ([]eh.Entity, error) {
+	return r.find(ctx, nil, 0, 0)
+}
+
+// FindAllPaged implements the FindAllPaged method of the
+// eventhorizon.PagedRepo interface.
+func (r *Repo) FindAllPaged(ctx context.Context, offset, limit int) ([]eh.Entity, int, error) {
+	sess := r.session.Copy()
+	defer sess.Close()
+
+	total, err := sess.DB(r.dbName(ctx)).C(r.collection).Count()
+	if err != nil {
+		return nil, 0, eh.RepoError{
+			Err:           ErrCouldNotLoadEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	result, err := r.find(ctx, nil, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, total, nil
+}
+
+// FindWithFilter implements the FindWithFilter method of the
+// eventhorizon.QueryRepo interface. The filter is used as the query document,
+// with fields named by their BSON tags.
+func (r *Repo) FindWithFilter(ctx context.Context, filter map[string]interface{}) ([]eh.Entity, error) {
+	return r.find(ctx, bson.M(filter), 0, 0)
+}
+
+// FindAllSorted implements the FindAllSorted method of the
+// eventhorizon.SortedRepo interface. The field is named by its BSON tag.
+func (r *Repo) FindAllSorted(ctx context.Context, field string, ascending bool) ([]eh.Entity, error) {
+	if !ascending {
+		field = "-" + field
+	}
+	return r.find(ctx, nil, 0, 0, field)
+}
+
+// find returns the entities matching the query, or all entities for a nil
+// query, skipping offset entities and returning at most limit if not 0.
+// The entities are sorted by the fields, if any, as for mgo.Query.Sort.
+func (r *Repo) find(ctx context.Context, q interface{}, offset, limit int, sort ...string) ([]eh.Entity, error) {
 	sess := r.session.Copy()
 	defer sess.Close()
 
@@ -155,7 +211,14 @@ func (r *Repo) FindAll(ctx context.Context) ([]eh.Entity, error) {
 			AggregateType: eh.AggregateTypeFromContext(ctx),
 		}
 	}
-	iter := sess.DB(r.dbName(ctx)).C(r.collection).Find(nil).Iter()
+	query := sess.DB(r.dbName(ctx)).C(r.collection).Find(q).Skip(offset).Limit(limit)
+	if len(sort) > 0 {
+		query = query.Sort(sort...)
+	}
+	if size := BatchSizeFromContext(ctx); size > 0 {
+		query = query.Batch(size)
+	}
+	iter := query.Iter()
 	result := []eh.Entity{}
 	entity := r.factoryFn()
 	for iter.Next(entity) {
@@ -298,6 +361,42 @@ func (r *Repo) Save(ctx context.Context, entity eh.Entity) error {
 	return nil
 }
 
+// SaveAll implements the SaveAll method of the eventhorizon.BulkRepo interface.
+// The entities are upserted in an unordered bulk operation.
+func (r *Repo) SaveAll(ctx context.Context, entities []eh.Entity) error {
+	sess := r.session.Copy()
+	defer sess.Close()
+
+	pairs := make([]interface{}, 0, 2*len(entities))
+	for _, entity := range entities {
+		if len(entity.EntityID()) == 0 {
+			return eh.RepoError{
+				Err:           eh.ErrCouldNotSaveEntity,
+				BaseErr:       eh.ErrMissingEntityID,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+		pairs = append(pairs, bson.M{"_id": entity.EntityID()}, entity)
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	bulk := sess.DB(r.dbName(ctx)).C(r.collection).Bulk()
+	bulk.Unordered()
+	bulk.Upsert(pairs...)
+	if _, err := bulk.Run(); err != nil {
+		return eh.RepoError{
+			Err:           eh.ErrCouldNotSaveEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return nil
+}
+
 // Remove implements the Remove method of the eventhorizon.WriteRepo interface.
 func (r *Repo) Remove(ctx context.Context, id string) error {
 	sess := r.session.Copy()
@@ -356,6 +455,24 @@ func (r *Repo) Close() {
 	r.session.Close()
 }
 
+type contextKey int
+
+const batchSizeKey contextKey = iota
+
+// NewContextWithBatchSize returns the context with the number of entities to
+// fetch from the DB per round trip in FindAll, to page through large
+// collections instead of fetching them at once.
+func NewContextWithBatchSize(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, batchSizeKey, size)
+}
+
+// BatchSizeFromContext returns the batch size set in the context, or 0 to use
+// the default batch size of the driver.
+func BatchSizeFromContext(ctx context.Context) int {
+	size, _ := ctx.Value(batchSizeKey).(int)
+	return size
+}
+
 // dbName appends the namespace, if one is set, to the DB prefix to
 // get the name of the DB to use.
 func (r *Repo) dbName(ctx context.Context) string {
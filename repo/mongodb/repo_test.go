@@ -184,6 +184,68 @@ func extraRepoTests(t *testing.T, ctx context.Context, r *Repo) {
 
 }
 
+func TestFindAllBatchSize(t *testing.T) {
+	ctx := eh.NewContextWithNamespaceAndType(context.Background(), "test_mongo", "mocks.Model")
+	if size := BatchSizeFromContext(ctx); size != 0 {
+		t.Error("there should be no batch size:", size)
+	}
+
+	// Local Mongo testing with Docker
+	url := os.Getenv("MONGO_HOST")
+
+	if url == "" {
+		// Default to localhost
+		url = "localhost:27017"
+	}
+	r, err := NewRepo(Options{
+		DBHost:     url,
+		DBName:     "test_mongo",
+		Collection: "mocks.Model.batch",
+	})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	defer r.Close()
+	r.SetEntityFactory(func() eh.Entity {
+		return &mocks.Model{}
+	})
+	defer func() {
+		if err := r.Clear(ctx); err != nil {
+			t.Error("there should be no error:", err)
+		}
+	}()
+
+	ids := map[string]bool{}
+	for i := 0; i < 250; i++ {
+		model := &mocks.Model{
+			ID:      uuid.New().String(),
+			Content: "model",
+		}
+		if err := r.Save(ctx, model); err != nil {
+			t.Fatal("there should be no error:", err)
+		}
+		ids[model.ID] = true
+	}
+
+	ctx = NewContextWithBatchSize(ctx, 10)
+	if size := BatchSizeFromContext(ctx); size != 10 {
+		t.Error("the batch size should be correct:", size)
+	}
+	result, err := r.FindAll(ctx)
+	if err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if len(result) != len(ids) {
+		t.Error("all models should be found:", len(result))
+	}
+	for _, entity := range result {
+		if !ids[entity.EntityID()] {
+			t.Error("the model should be one of the saved:", entity)
+		}
+		delete(ids, entity.EntityID())
+	}
+}
+
 func TestRepository(t *testing.T) {
 	if r := Repository(nil); r != nil {
 		t.Error("the parent repository should be nil:", r)
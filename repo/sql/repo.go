@@ -0,0 +1,434 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sql is a read repository that stores entities as JSON in a SQL
+// table, using database/sql. The table must have the following columns, here
+// in SQLite syntax with the default body column:
+//
+//	CREATE TABLE entities (
+//	    seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+//	    namespace TEXT NOT NULL,
+//	    id        TEXT NOT NULL,
+//	    body      TEXT NOT NULL,
+//	    UNIQUE (namespace, id)
+//	);
+//
+// The seq column keeps the insert order for FindAll. Saves use an upsert with
+// ON CONFLICT, which is supported by SQLite and PostgreSQL.
+//
+// The repo does not implement eh.QueryRepo or eh.SortedRepo. Querying JSON
+// differs between SQL dialects, json_extract in SQLite needs the JSON1
+// extension and PostgreSQL uses the ->> operator, and filtering or sorting the
+// bodies after reading them would not push any predicates to the database.
+// Read models that need queries should use a table with columns for the
+// queried fields instead.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+
+	eh "github.com/firawe/eventhorizon"
+)
+
+// ErrNoDB is when no database is set.
+var ErrNoDB = errors.New("no database")
+
+// ErrInvalidOptions is when the table or column names can not be used.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// ErrCouldNotClearDB is when the database could not be cleared.
+var ErrCouldNotClearDB = errors.New("could not clear database")
+
+// ErrModelNotSet is when an model factory is not set on the Repo.
+var ErrModelNotSet = errors.New("model not set")
+
+// ErrCouldNotLoadEntity is when an entity could not be loaded.
+var ErrCouldNotLoadEntity = errors.New("could not load entity")
+
+// Repo implements a SQL repository for entities.
+type Repo struct {
+	db        *sql.DB
+	table     string
+	body      string
+	numbered  bool
+	factoryFn func() eh.Entity
+}
+
+// Options are the options for the SQL repository.
+type Options struct {
+	// Table is the name of the table, "entities" by default.
+	Table string
+	// BodyColumn is the name of the JSON column for the entity, "body" by default.
+	BodyColumn string
+	// NumberedPlaceholders uses $1, $2 etc as query placeholders instead of
+	// ?, as required by PostgreSQL.
+	NumberedPlaceholders bool
+}
+
+// identifier is the allowed format of table and column names.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// NewRepo creates a new Repo using a database.
+func NewRepo(db *sql.DB, options Options) (*Repo, error) {
+	if db == nil {
+		return nil, ErrNoDB
+	}
+	if options.Table == "" {
+		options.Table = "entities"
+	}
+	if options.BodyColumn == "" {
+		options.BodyColumn = "body"
+	}
+	if !identifier.MatchString(options.Table) || !identifier.MatchString(options.BodyColumn) {
+		return nil, ErrInvalidOptions
+	}
+
+	r := &Repo{
+		db:       db,
+		table:    options.Table,
+		body:     options.BodyColumn,
+		numbered: options.NumberedPlaceholders,
+	}
+
+	return r, nil
+}
+
+// Parent implements the Parent method of the eventhorizon.ReadRepo interface.
+func (r *Repo) Parent() eh.ReadRepo {
+	return nil
+}
+
+// Find implements the Find method of the eventhorizon.ReadRepo interface.
+func (r *Repo) Find(ctx context.Context, id string) (eh.Entity, error) {
+	if r.factoryFn == nil {
+		return nil, eh.RepoError{
+			Err:           ErrModelNotSet,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	var body []byte
+	err := r.db.QueryRowContext(ctx, r.query(
+		"SELECT %[2]s FROM %[1]s WHERE namespace = %[3]s AND id = %[4]s", 2),
+		eh.NamespaceFromContext(ctx), id,
+	).Scan(&body)
+	if err == sql.ErrNoRows {
+		return nil, eh.RepoError{
+			Err:           eh.ErrEntityNotFound,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	} else if err != nil {
+		return nil, eh.RepoError{
+			Err:           ErrCouldNotLoadEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	entity := r.factoryFn()
+	if err := json.Unmarshal(body, entity); err != nil {
+		return nil, eh.RepoError{
+			Err:           ErrCouldNotLoadEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return entity, nil
+}
+
+// FindAll implements the FindAll method of the eventhorizon.ReadRepo interface.
+// The entities are returned in the order they were first saved.
+func (r *Repo) FindAll(ctx context.Context) ([]eh.Entity, error) {
+	result, _, err := r.FindAllPaged(ctx, 0, 0)
+	return result, err
+}
+
+// FindAllPaged implements the FindAllPaged method of the
+// eventhorizon.PagedRepo interface.
+func (r *Repo) FindAllPaged(ctx context.Context, offset, limit int) ([]eh.Entity, int, error) {
+	if r.factoryFn == nil {
+		return nil, 0, eh.RepoError{
+			Err:           ErrModelNotSet,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, r.query(
+		"SELECT COUNT(*) FROM %[1]s WHERE namespace = %[3]s", 1),
+		eh.NamespaceFromContext(ctx),
+	).Scan(&total); err != nil {
+		return nil, 0, eh.RepoError{
+			Err:           ErrCouldNotLoadEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+	// Both SQLite and PostgreSQL need a limit to use an offset.
+	rowLimit := int64(limit)
+	if limit < 1 {
+		rowLimit = math.MaxInt64
+	}
+	rows, err := r.db.QueryContext(ctx, r.query(
+		"SELECT %[2]s FROM %[1]s WHERE namespace = %[3]s ORDER BY seq LIMIT %[4]s OFFSET %[5]s", 3),
+		eh.NamespaceFromContext(ctx), rowLimit, offset,
+	)
+	if err != nil {
+		return nil, 0, eh.RepoError{
+			Err:           ErrCouldNotLoadEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	result, err := r.scan(ctx, rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, total, nil
+}
+
+// scan returns the entities of the rows, and closes the rows.
+func (r *Repo) scan(ctx context.Context, rows *sql.Rows) ([]eh.Entity, error) {
+	defer rows.Close()
+
+	result := []eh.Entity{}
+	for rows.Next() {
+		var body []byte
+		if err := rows.Scan(&body); err != nil {
+			return nil, eh.RepoError{
+				Err:           ErrCouldNotLoadEntity,
+				BaseErr:       err,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+		entity := r.factoryFn()
+		if err := json.Unmarshal(body, entity); err != nil {
+			return nil, eh.RepoError{
+				Err:           ErrCouldNotLoadEntity,
+				BaseErr:       err,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+		result = append(result, entity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, eh.RepoError{
+			Err:           ErrCouldNotLoadEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	return result, nil
+}
+
+// Save implements the Save method of the eventhorizon.WriteRepo interface.
+// Saving an existing entity keeps its place in the insert order.
+func (r *Repo) Save(ctx context.Context, entity eh.Entity) error {
+	if len(entity.EntityID()) == 0 {
+		return eh.RepoError{
+			Err:           eh.ErrCouldNotSaveEntity,
+			BaseErr:       eh.ErrMissingEntityID,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	body, err := json.Marshal(entity)
+	if err != nil {
+		return eh.RepoError{
+			Err:           eh.ErrCouldNotSaveEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	if _, err := r.db.ExecContext(ctx, r.query(
+		"INSERT INTO %[1]s (namespace, id, %[2]s) VALUES (%[3]s, %[4]s, %[5]s) "+
+			"ON CONFLICT (namespace, id) DO UPDATE SET %[2]s = excluded.%[2]s", 3),
+		eh.NamespaceFromContext(ctx), entity.EntityID(), string(body),
+	); err != nil {
+		return eh.RepoError{
+			Err:           eh.ErrCouldNotSaveEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return nil
+}
+
+// SaveAll implements the SaveAll method of the eventhorizon.BulkRepo interface.
+// The entities are saved in one transaction.
+func (r *Repo) SaveAll(ctx context.Context, entities []eh.Entity) error {
+	bodies := make([]string, len(entities))
+	for i, entity := range entities {
+		if len(entity.EntityID()) == 0 {
+			return eh.RepoError{
+				Err:           eh.ErrCouldNotSaveEntity,
+				BaseErr:       eh.ErrMissingEntityID,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+		body, err := json.Marshal(entity)
+		if err != nil {
+			return eh.RepoError{
+				Err:           eh.ErrCouldNotSaveEntity,
+				BaseErr:       err,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+		bodies[i] = string(body)
+	}
+
+	if err := r.saveAll(ctx, entities, bodies); err != nil {
+		return eh.RepoError{
+			Err:           eh.ErrCouldNotSaveEntity,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return nil
+}
+
+// saveAll upserts the entities with their JSON bodies in a transaction.
+func (r *Repo) saveAll(ctx context.Context, entities []eh.Entity, bodies []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, r.query(
+		"INSERT INTO %[1]s (namespace, id, %[2]s) VALUES (%[3]s, %[4]s, %[5]s) "+
+			"ON CONFLICT (namespace, id) DO UPDATE SET %[2]s = excluded.%[2]s", 3))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, entity := range entities {
+		if _, err := stmt.ExecContext(ctx,
+			eh.NamespaceFromContext(ctx), entity.EntityID(), bodies[i],
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Remove implements the Remove method of the eventhorizon.WriteRepo interface.
+func (r *Repo) Remove(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, r.query(
+		"DELETE FROM %[1]s WHERE namespace = %[3]s AND id = %[4]s", 2),
+		eh.NamespaceFromContext(ctx), id,
+	)
+	if err != nil {
+		return eh.RepoError{
+			Err:           eh.ErrEntityNotFound,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return eh.RepoError{
+			Err:           eh.ErrEntityNotFound,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+
+	return nil
+}
+
+// SetEntityFactory sets a factory function that creates concrete entity types.
+func (r *Repo) SetEntityFactory(f func() eh.Entity) {
+	r.factoryFn = f
+}
+
+// Clear removes all entities in the namespace of the context.
+func (r *Repo) Clear(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, r.query(
+		"DELETE FROM %[1]s WHERE namespace = %[3]s", 1),
+		eh.NamespaceFromContext(ctx),
+	); err != nil {
+		return eh.RepoError{
+			Err:           ErrCouldNotClearDB,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return nil
+}
+
+// Close closes the database.
+func (r *Repo) Close() error {
+	return r.db.Close()
+}
+
+// query formats a query with the table as %[1]s, the body column as %[2]s and
+// n placeholders as %[3]s and on.
+func (r *Repo) query(format string, n int) string {
+	args := []interface{}{r.table, r.body}
+	for i := 1; i <= n; i++ {
+		if r.numbered {
+			args = append(args, fmt.Sprintf("$%d", i))
+		} else {
+			args = append(args, "?")
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Repository returns a parent ReadRepo if there is one.
+func Repository(repo eh.ReadRepo) *Repo {
+	if repo == nil {
+		return nil
+	}
+
+	if r, ok := repo.(*Repo); ok {
+		return r
+	}
+
+	return Repository(repo.Parent())
+}
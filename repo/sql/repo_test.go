@@ -0,0 +1,140 @@
+// Copyright (c) 2017 - The Event Horizon authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	eh "github.com/firawe/eventhorizon"
+	"github.com/firawe/eventhorizon/mocks"
+	"github.com/firawe/eventhorizon/repo"
+)
+
+func TestReadRepo(t *testing.T) {
+	r := newTestRepo(t, Options{Table: "models", BodyColumn: "data"})
+	defer r.Close()
+	if r.Parent() != nil {
+		t.Error("the parent repo should be nil")
+	}
+
+	// Repo with default namespace.
+	repo.AcceptanceTest(t, context.Background(), r)
+
+	// Repo with other namespace.
+	ctx := eh.NewContextWithNamespace(context.Background(), "ns")
+	repo.AcceptanceTest(t, ctx, r)
+
+	t.Log("clear a namespace")
+	if err := r.Save(ctx, &mocks.Model{ID: "id"}); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if err := r.Save(context.Background(), &mocks.Model{ID: "id"}); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if err := r.Clear(ctx); err != nil {
+		t.Error("there should be no error:", err)
+	}
+	if result, err := r.FindAll(ctx); err != nil || len(result) != 0 {
+		t.Error("there should be no items:", result, err)
+	}
+	if result, err := r.FindAll(context.Background()); err != nil || len(result) != 2 {
+		t.Error("the other namespace should be kept:", result, err)
+	}
+}
+
+func TestNewRepo(t *testing.T) {
+	if _, err := NewRepo(nil, Options{}); err != ErrNoDB {
+		t.Error("there should be a ErrNoDB error:", err)
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	defer db.Close()
+	for _, options := range []Options{
+		{Table: "models; DROP TABLE models"},
+		{BodyColumn: "data = 1, id"},
+	} {
+		if _, err := NewRepo(db, options); err != ErrInvalidOptions {
+			t.Error("there should be a ErrInvalidOptions error:", options, err)
+		}
+	}
+
+	t.Log("numbered placeholders")
+	r, err := NewRepo(db, Options{NumberedPlaceholders: true})
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if q := r.query("DELETE FROM %[1]s WHERE namespace = %[3]s AND id = %[4]s", 2); q != "DELETE FROM entities WHERE namespace = $1 AND id = $2" {
+		t.Error("the query should be correct:", q)
+	}
+
+	t.Log("model not set")
+	_, err = r.Find(context.Background(), "id")
+	if rrErr, ok := err.(eh.RepoError); !ok || rrErr.Err != ErrModelNotSet {
+		t.Error("there should be a ErrModelNotSet error:", err)
+	}
+}
+
+func TestRepository(t *testing.T) {
+	if r := Repository(nil); r != nil {
+		t.Error("the parent repository should be nil:", r)
+	}
+
+	inner := &mocks.Repo{}
+	if r := Repository(inner); r != nil {
+		t.Error("the parent repository should be nil:", r)
+	}
+
+	repo := newTestRepo(t, Options{})
+	defer repo.Close()
+	outer := &mocks.Repo{ParentRepo: repo}
+	if r := Repository(outer); r != repo {
+		t.Error("the parent repository should be correct:", r)
+	}
+}
+
+// newTestRepo creates a repo for mocks.Model in an in-memory SQLite DB.
+func newTestRepo(t *testing.T, options Options) *Repo {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	// Every connection has its own in-memory DB.
+	db.SetMaxOpenConns(1)
+
+	r, err := NewRepo(db, options)
+	if err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE ` + r.table + ` (
+		seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+		namespace TEXT NOT NULL,
+		id        TEXT NOT NULL,
+		` + r.body + ` TEXT NOT NULL,
+		UNIQUE (namespace, id)
+	)`); err != nil {
+		t.Fatal("there should be no error:", err)
+	}
+	r.SetEntityFactory(func() eh.Entity {
+		return &mocks.Model{}
+	})
+	return r
+}
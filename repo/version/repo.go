@@ -81,6 +81,74 @@ func (r *Repo) Find(ctx context.Context, id string) (eh.Entity, error) {
 	}
 }
 
+// FindAllPaged implements the FindAllPaged method of the
+// eventhorizon.PagedRepo interface, if the parent repo implements it.
+func (r *Repo) FindAllPaged(ctx context.Context, offset, limit int) ([]eh.Entity, int, error) {
+	parent, ok := r.ReadWriteRepo.(eh.PagedRepo)
+	if !ok {
+		return nil, 0, eh.RepoError{
+			Err:           eh.ErrUnsupportedQuery,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return parent.FindAllPaged(ctx, offset, limit)
+}
+
+// FindAllSorted implements the FindAllSorted method of the
+// eventhorizon.SortedRepo interface, if the parent repo implements it.
+func (r *Repo) FindAllSorted(ctx context.Context, field string, ascending bool) ([]eh.Entity, error) {
+	parent, ok := r.ReadWriteRepo.(eh.SortedRepo)
+	if !ok {
+		return nil, eh.RepoError{
+			Err:           eh.ErrUnsupportedQuery,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return parent.FindAllSorted(ctx, field, ascending)
+}
+
+// FindWithFilter implements the FindWithFilter method of the
+// eventhorizon.QueryRepo interface, if the parent repo implements it.
+func (r *Repo) FindWithFilter(ctx context.Context, filter map[string]interface{}) ([]eh.Entity, error) {
+	parent, ok := r.ReadWriteRepo.(eh.QueryRepo)
+	if !ok {
+		return nil, eh.RepoError{
+			Err:           eh.ErrUnsupportedQuery,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: eh.AggregateTypeFromContext(ctx),
+		}
+	}
+	return parent.FindWithFilter(ctx, filter)
+}
+
+// SaveAll implements the SaveAll method of the eventhorizon.BulkRepo interface.
+// The entities are saved one by one if the parent repo does not implement it.
+func (r *Repo) SaveAll(ctx context.Context, entities []eh.Entity) error {
+	if parent, ok := r.ReadWriteRepo.(eh.BulkRepo); ok {
+		return parent.SaveAll(ctx, entities)
+	}
+
+	// Save one by one, after checking all IDs like a bulk save.
+	for _, entity := range entities {
+		if len(entity.EntityID()) == 0 {
+			return eh.RepoError{
+				Err:           eh.ErrCouldNotSaveEntity,
+				BaseErr:       eh.ErrMissingEntityID,
+				Namespace:     eh.NamespaceFromContext(ctx),
+				AggregateType: eh.AggregateTypeFromContext(ctx),
+			}
+		}
+	}
+	for _, entity := range entities {
+		if err := r.ReadWriteRepo.Save(ctx, entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // findMinVersion finds an item if it has a version and it is at least minVersion.
 func (r *Repo) findMinVersion(ctx context.Context, id string, minVersion int) (eh.Entity, error) {
 	entity, err := r.ReadWriteRepo.Find(ctx, id)
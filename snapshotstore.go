@@ -2,8 +2,13 @@ package eventhorizon
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrSnapshotNotFound is returned by a SnapshotStore when an aggregate has no
+// snapshot.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
 type SnapshotStoreError struct {
 	// Err is the error.
 	Err error
@@ -29,3 +34,13 @@ type SnapshotStore interface {
 
 	Load(context.Context, AggregateType, string, int) (Aggregate, error)
 }
+
+// SnapshotDeleter is a SnapshotStore that can delete all snapshots of an
+// aggregate, for example when deleting the aggregate to erase personal data.
+type SnapshotDeleter interface {
+	SnapshotStore
+
+	// Delete deletes all snapshots of an aggregate, it is not an error if
+	// there are none.
+	Delete(context.Context, AggregateType, string) error
+}
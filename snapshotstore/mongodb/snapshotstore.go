@@ -35,6 +35,9 @@ var ErrCouldNotLoadSnapshot = errors.New("could not load snapshot")
 // ErrCouldNotSaveSnapshot is when an aggregate could not be saved.
 var ErrCouldNotSaveSnapshot = errors.New("could not save snapshot")
 
+// ErrCouldNotDeleteSnapshots is when the snapshots of an aggregate could not be deleted.
+var ErrCouldNotDeleteSnapshots = errors.New("could not delete snapshots")
+
 type SnapshotStore struct {
 	session        *mgo.Session
 	SingleSnapshot bool
@@ -181,6 +184,23 @@ func (s *SnapshotStore) Save(ctx context.Context, aggregate eh.Aggregate) error
 	return err
 }
 
+// Delete implements the Delete method of the eventhorizon.SnapshotDeleter interface.
+func (s *SnapshotStore) Delete(ctx context.Context, aggregateType eh.AggregateType, id string) error {
+	sess := s.session.Copy()
+	defer sess.Close()
+	if _, err := sess.DB(s.dbName(ctx)).C(s.colName(ctx) + ".snapshots").RemoveAll(bson.M{
+		"aggregate_id": id,
+	}); err != nil {
+		return eh.SnapshotStoreError{
+			Err:           ErrCouldNotDeleteSnapshots,
+			BaseErr:       err,
+			Namespace:     eh.NamespaceFromContext(ctx),
+			AggregateType: string(aggregateType),
+		}
+	}
+	return nil
+}
+
 func (s *SnapshotStore) Clear(ctx context.Context) error {
 	sess := s.session.Copy()
 	defer sess.Close()